@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/programme-lv/fs-task-format-parser/pkg/fstaskparser"
+)
+
+func runConvert(args []string) error {
+	fs := flag.NewFlagSet("convert", flag.ExitOnError)
+	from := fs.String("from", "", "source format, detected from the package layout if empty")
+	to := fs.String("to", "fstask", "destination format: "+formatNames())
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		return fmt.Errorf("usage: fstask convert [-from format] [-to format] <source> <destination>")
+	}
+
+	dst, err := fstaskparser.LookupFormat(*to)
+	if err != nil {
+		return err
+	}
+	task, err := importTask(fs.Arg(0), *from)
+	if err != nil {
+		return err
+	}
+
+	exportReport, err := dst.Export(task, fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, exportReport)
+	return nil
+}
+
+// importTask reads a task in the named format, detected if empty, and
+// prints the import report to stderr
+func importTask(path string, format string) (*fstaskparser.Task, error) {
+	var f fstaskparser.Format
+	var err error
+	if format == "" {
+		f, err = fstaskparser.DetectFormat(path)
+	} else {
+		f, err = fstaskparser.LookupFormat(format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	task, report, err := f.Import(path)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintln(os.Stderr, report)
+	return task, nil
+}
+
+func formatNames() string {
+	names := make([]string, 0)
+	for _, f := range fstaskparser.Formats() {
+		names = append(names, f.Name())
+	}
+	return strings.Join(names, ", ")
+}
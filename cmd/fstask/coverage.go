@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/programme-lv/fs-task-format-parser/pkg/fstaskparser"
+)
+
+func runCoverage(args []string) error {
+	fs := flag.NewFlagSet("coverage", flag.ExitOnError)
+	require := fs.String("require", "", "comma separated languages that must be complete, e.g. \"lv,en\"")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: fstask coverage [-require lv,en] <task directory>")
+	}
+
+	task, err := fstaskparser.Read(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "LANGUAGE\tMD SECTIONS\tPDF\tEXAMPLE NOTES\tORIGIN NOTES\tMISSING")
+	for _, c := range task.LanguageCoverage() {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\n", c.Language, orDash(strings.Join(c.MDSections, ",")),
+			yesNo(c.PDF), c.ExampleNotes, yesNo(c.OriginNotes), orDash(strings.Join(c.Missing, ",")))
+	}
+	w.Flush()
+
+	if *require != "" {
+		return task.CheckRequiredLanguages(strings.Split(*require, ","))
+	}
+	return nil
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
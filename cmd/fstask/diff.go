@@ -0,0 +1,40 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+
+	"github.com/programme-lv/fs-task-format-parser/pkg/fstaskparser"
+)
+
+func runDiff(args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "print the differences as JSON")
+	from := fs.String("from", "", "format of both tasks, detected from the package layout if empty")
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		return fmt.Errorf("usage: fstask diff [-json] [-from format] <old task> <new task>")
+	}
+
+	a, err := importTask(fs.Arg(0), *from)
+	if err != nil {
+		return err
+	}
+	b, err := importTask(fs.Arg(1), *from)
+	if err != nil {
+		return err
+	}
+
+	d := fstaskparser.Diff(a, b)
+	if *asJSON {
+		content, err := json.MarshalIndent(d, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error encoding diff: %w", err)
+		}
+		fmt.Println(string(content))
+		return nil
+	}
+	fmt.Println(d)
+	return nil
+}
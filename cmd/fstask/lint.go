@@ -0,0 +1,40 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/programme-lv/fs-task-format-parser/pkg/fstaskparser"
+)
+
+func runLint(args []string) error {
+	fs := flag.NewFlagSet("lint", flag.ExitOnError)
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: fstask lint <task directory>")
+	}
+
+	task, err := fstaskparser.Read(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	problems := 0
+	for _, p := range task.LintStatementMath() {
+		fmt.Println(p)
+		problems++
+	}
+	for _, p := range task.LintStatementTemplates() {
+		fmt.Println(p)
+		problems++
+	}
+	for _, r := range task.FindMissingAssetReferences() {
+		fmt.Println(r)
+		problems++
+	}
+
+	if problems > 0 {
+		return fmt.Errorf("found %d problems", problems)
+	}
+	return nil
+}
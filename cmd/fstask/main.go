@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+type command struct {
+	name  string
+	usage string
+	run   func(args []string) error
+}
+
+var commands = []command{
+	{name: "schema", usage: "emit the problem.toml JSON Schema", run: runSchema},
+	{name: "lint", usage: "check statement math, placeholders and asset links", run: runLint},
+	{name: "coverage", usage: "report statement translation completeness", run: runCoverage},
+	{name: "convert", usage: "convert a task between package formats", run: runConvert},
+	{name: "diff", usage: "compare limits, tests, test groups and statements of two tasks", run: runDiff},
+}
+
+func main() {
+	flag.Usage = printUsage
+	flag.Parse()
+	if flag.NArg() < 1 {
+		printUsage()
+		os.Exit(2)
+	}
+
+	for _, c := range commands {
+		if c.name == flag.Arg(0) {
+			err := c.run(flag.Args()[1:])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s: %v\n", c.name, err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "unknown command: %s\n", flag.Arg(0))
+	printUsage()
+	os.Exit(2)
+}
+
+func printUsage() {
+	fmt.Fprintf(os.Stderr, "usage: fstask <command> [arguments]\n\ncommands:\n")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", c.name, c.usage)
+	}
+}
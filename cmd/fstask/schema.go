@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/programme-lv/fs-task-format-parser/pkg/fstaskparser"
+)
+
+func runSchema(args []string) error {
+	fs := flag.NewFlagSet("schema", flag.ExitOnError)
+	version := fs.String("version", "", "specification version, defaults to the latest")
+	outDir := fs.String("o", "", "write a schema file for every supported version into this directory")
+	fs.Parse(args)
+
+	if *outDir != "" {
+		for _, v := range fstaskparser.SupportedSpecVersions() {
+			schema, err := fstaskparser.ProblemTomlJSONSchema(v)
+			if err != nil {
+				return err
+			}
+			path := filepath.Join(*outDir, fmt.Sprintf("problem-toml-%s.schema.json", v))
+			err = os.WriteFile(path, schema, 0644)
+			if err != nil {
+				return fmt.Errorf("error writing schema: %w", err)
+			}
+		}
+		return nil
+	}
+
+	versions := fstaskparser.SupportedSpecVersions()
+	specVers := versions[len(versions)-1]
+	if *version != "" {
+		var err error
+		specVers, err = fstaskparser.ParseSpecVersion(*version)
+		if err != nil {
+			return err
+		}
+	}
+
+	schema, err := fstaskparser.ProblemTomlJSONSchema(specVers)
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(schema)
+	return err
+}
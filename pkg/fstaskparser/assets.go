@@ -0,0 +1,112 @@
+package fstaskparser
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// MissingAssetReference is an image link in a Markdown statement
+// or example note that does not point to an existing asset.
+type MissingAssetReference struct {
+	Language string // empty for example notes
+	Section  string // "story", "input", "output", "notes", "scoring", an additional section or "examples/<name>"
+	Line     int
+	Target   string // link target as written in the Markdown
+}
+
+func (r MissingAssetReference) String() string {
+	if r.Language == "" {
+		return fmt.Sprintf("%s:%d: missing asset %q", r.Section, r.Line, r.Target)
+	}
+	return fmt.Sprintf("%s/%s:%d: missing asset %q", r.Language, r.Section, r.Line, r.Target)
+}
+
+// matches ![alt](target) and ![alt](<target> "title")
+var mdImageLinkRegexp = regexp.MustCompile(`!\[[^\]]*\]\(\s*<?([^)\s>]+)>?(?:\s+"[^"]*")?\s*\)`)
+
+var urlSchemeRegexp = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*:`)
+
+// FindMissingAssetReferences checks that every image link in the Markdown
+// statements and example notes points to an existing asset. Links are
+// resolved relative to the assets directory, an "assets/" prefix is allowed.
+// External links such as "https://..." and "data:" URIs are not checked.
+func (t *Task) FindMissingAssetReferences() []MissingAssetReference {
+	existing := make(map[string]bool, len(t.assets))
+	for _, a := range t.assets {
+		existing[a.RelativePath] = true
+	}
+
+	res := make([]MissingAssetReference, 0)
+	t.forEachMarkdownSection(func(lang string, section string, md string) {
+		for _, link := range findMdImageLinks(md) {
+			if urlSchemeRegexp.MatchString(link.Target) {
+				continue
+			}
+			if !existing[normalizeAssetPath(link.Target)] {
+				res = append(res, MissingAssetReference{
+					Language: lang,
+					Section:  section,
+					Line:     link.Line,
+					Target:   link.Target,
+				})
+			}
+		}
+	})
+
+	return res
+}
+
+// forEachMarkdownSection calls f for every section of the Markdown statements
+// including additional sections, and for every example note. Example notes have an empty language and
+// section "examples/<name>".
+func (t *Task) forEachMarkdownSection(f func(lang string, section string, md string)) {
+	for _, st := range t.mdStatements {
+		lang := ""
+		if st.Language != nil {
+			lang = *st.Language
+		}
+		f(lang, "story", st.Story)
+		f(lang, "input", st.Input)
+		f(lang, "output", st.Output)
+		if st.Notes != nil {
+			f(lang, "notes", *st.Notes)
+		}
+		if st.Scoring != nil {
+			f(lang, "scoring", *st.Scoring)
+		}
+		for _, name := range sortedSectionNames(st.Sections) {
+			f(lang, name, st.Sections[name])
+		}
+	}
+
+	for i, e := range t.examples {
+		name := fmt.Sprintf("%03d", i+1)
+		if e.Name != nil {
+			name = *e.Name
+		}
+		f("", "examples/"+name, string(e.MdNote))
+	}
+}
+
+type mdImageLink struct {
+	Line   int
+	Target string
+}
+
+func findMdImageLinks(md string) []mdImageLink {
+	res := make([]mdImageLink, 0)
+	for i, line := range strings.Split(md, "\n") {
+		for _, m := range mdImageLinkRegexp.FindAllStringSubmatch(line, -1) {
+			res = append(res, mdImageLink{Line: i + 1, Target: m[1]})
+		}
+	}
+	return res
+}
+
+// normalizeAssetPath maps a link target to a path relative to the assets directory
+func normalizeAssetPath(target string) string {
+	p := path.Clean(strings.TrimPrefix(target, "/"))
+	return strings.TrimPrefix(p, "assets/")
+}
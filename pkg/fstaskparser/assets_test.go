@@ -0,0 +1,67 @@
+package fstaskparser_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/programme-lv/fs-task-format-parser/pkg/fstaskparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadingWritingNestedAssets(t *testing.T) {
+	parsedTask, err := fstaskparser.Read(testTaskPath)
+	require.NoErrorf(t, err, "failed to read task: %v", err)
+
+	// the test task statement links images that are not among its assets
+	missing := parsedTask.FindMissingAssetReferences()
+	require.Equal(t, 2, len(missing))
+	assert.Equal(t, "lv", missing[0].Language)
+	assert.Equal(t, "story", missing[0].Section)
+	assert.Equal(t, "example1.png", missing[0].Target)
+	assert.Equal(t, "example2.png", missing[1].Target)
+
+	tmpDirectory, err := os.MkdirTemp("", "fstaskparser-test-")
+	require.NoErrorf(t, err, "failed to create temporary directory: %v", err)
+	defer os.RemoveAll(tmpDirectory)
+
+	outputDirectory := filepath.Join(tmpDirectory, "kvadrputekl")
+	err = parsedTask.Store(outputDirectory)
+	require.NoErrorf(t, err, "failed to store task: %v", err)
+
+	imgDir := filepath.Join(outputDirectory, "assets", "img")
+	err = os.MkdirAll(imgDir, 0755)
+	require.NoErrorf(t, err, "failed to create directory: %v", err)
+	for _, fname := range []string{"example1.png", "example2.png"} {
+		err = os.WriteFile(filepath.Join(imgDir, fname), []byte(fname), 0644)
+		require.NoErrorf(t, err, "failed to write asset: %v", err)
+	}
+
+	storyPath := filepath.Join(outputDirectory, "statements", "md", "lv", "story.md")
+	story, err := os.ReadFile(storyPath)
+	require.NoErrorf(t, err, "failed to read story.md: %v", err)
+	story = []byte(
+		string(story) + "\n![3. attēls](img/example1.png)\n![4. attēls](./assets/img/example2.png \"title\")\n" +
+			"![ārējs](https://example.com/x.png)\n")
+	err = os.WriteFile(storyPath, story, 0644)
+	require.NoErrorf(t, err, "failed to write story.md: %v", err)
+
+	storedTask, err := fstaskparser.Read(outputDirectory)
+	require.NoErrorf(t, err, "failed to read task: %v", err)
+
+	assetPaths := []string{}
+	for _, a := range storedTask.GetAssets() {
+		assetPaths = append(assetPaths, a.RelativePath)
+	}
+	assert.Equal(t, []string{"illustration.png", "img/example1.png", "img/example2.png"}, assetPaths)
+	assert.Equal(t, 2, len(storedTask.FindMissingAssetReferences()))
+
+	anotherOutputDirectory := filepath.Join(tmpDirectory, "kvadrputekl2")
+	err = storedTask.Store(anotherOutputDirectory)
+	require.NoErrorf(t, err, "failed to store task: %v", err)
+
+	content, err := os.ReadFile(filepath.Join(anotherOutputDirectory, "assets", "img", "example2.png"))
+	require.NoErrorf(t, err, "failed to read asset: %v", err)
+	assert.Equal(t, "example2.png", string(content))
+}
@@ -0,0 +1,582 @@
+package fstaskparser
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// cmsTaskYAML is task.yaml of the CMS Italian format
+type cmsTaskYAML struct {
+	Name                string  `yaml:"name"`
+	Title               string  `yaml:"title"`
+	TimeLimit           float64 `yaml:"time_limit"`
+	MemoryLimit         int     `yaml:"memory_limit"`
+	NInput              int     `yaml:"n_input"`
+	ScoreType           string  `yaml:"score_type,omitempty"`
+	ScoreTypeParameters any     `yaml:"score_type_parameters,omitempty"`
+	PublicTestcases     string  `yaml:"public_testcases,omitempty"`
+	Infile              string  `yaml:"infile"`
+	Outfile             string  `yaml:"outfile"`
+	PrimaryLanguage     string  `yaml:"primary_language,omitempty"`
+}
+
+// cmsKnownKeys are the task.yaml keys that carry nothing the task needs
+var cmsKnownKeys = map[string]bool{
+	"token_mode":              true,
+	"token_max_number":        true,
+	"token_min_interval":      true,
+	"token_gen_initial":       true,
+	"token_gen_number":        true,
+	"token_gen_interval":      true,
+	"token_gen_max":           true,
+	"max_submission_number":   true,
+	"min_submission_interval": true,
+	"score_precision":         true,
+	"feedback_level":          true,
+}
+
+// CMSExportOptions control ExportCMS.
+type CMSExportOptions struct {
+	// ShortName is task.yaml "name", the base name of the directory if empty
+	ShortName string
+	// PrimaryLanguage selects the PDF stored as statement/statement.pdf,
+	// the alphabetically first PDF language if empty
+	PrimaryLanguage string
+}
+
+var cmsSubtaskLineRegexp = regexp.MustCompile(`^#\s*ST\s*:\s*([0-9.]+)`)
+
+var cmsTestNameRegexp = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// ExportCMS writes the task in the CMS Italian YAML format. Every test group
+// becomes a GroupMin subtask; the GEN file lists the test filenames under
+// "#ST: <points>" lines, in the order of input/input<i>.txt.
+func (t *Task) ExportCMS(dirPath string, opts CMSExportOptions) (*ExportReport, error) {
+	report := &ExportReport{Format: "cms"}
+	log.Printf("Exporting task to CMS directory: %s\n", dirPath)
+	if _, err := os.Stat(dirPath); !os.IsNotExist(err) {
+		return nil, fmt.Errorf("directory already exists: %s", dirPath)
+	}
+
+	shortName := opts.ShortName
+	if shortName == "" {
+		shortName = filepath.Base(dirPath)
+	}
+
+	// subtasks in test group order, ungrouped tests first
+	type exportedSubtask struct {
+		points  int
+		public  bool
+		testIDs []int
+	}
+	subtasks := make([]exportedSubtask, 0, len(t.testGroupIDs)+1)
+	grouped := make(map[int]bool)
+	for _, groupID := range t.testGroupIDs {
+		for _, id := range t.tGroupTestIDs[groupID] {
+			grouped[id] = true
+		}
+	}
+	ungrouped := make([]int, 0)
+	for _, test := range t.GetTestsSortedByID() {
+		if !grouped[test.ID] {
+			ungrouped = append(ungrouped, test.ID)
+		}
+	}
+	if len(t.testGroupIDs) > 0 && len(ungrouped) > 0 {
+		report.dropped("%d tests outside of groups exported as a 0 point subtask", len(ungrouped))
+		subtasks = append(subtasks, exportedSubtask{points: 0, testIDs: ungrouped})
+	}
+	subtaskNumbers := make(map[int]bool)
+	for _, groupID := range t.testGroupIDs {
+		subtasks = append(subtasks, exportedSubtask{
+			points:  t.tGroupPoints[groupID],
+			public:  t.isTGroupPublic[groupID],
+			testIDs: t.tGroupTestIDs[groupID],
+		})
+		subtaskNumbers[t.tGroupToStMap[groupID]] = true
+	}
+	if len(subtaskNumbers) != len(t.testGroupIDs) {
+		report.dropped("subtasks with several test groups, every group becomes its own subtask")
+	}
+
+	testsByID := make(map[int]test, len(t.tests))
+	for _, test := range t.tests {
+		testsByID[test.ID] = test
+	}
+
+	var gen bytes.Buffer
+	gen.WriteString("# tests are listed in the order of input/input<i>.txt\n")
+	publicTests := make([]string, 0)
+	testIndex := 0
+	params := make([][]int, 0, len(subtasks))
+	files := make(map[string][]byte)
+	writeTest := func(id int) {
+		test := testsByID[id]
+		files[fmt.Sprintf("input/input%d.txt", testIndex)] = test.Input
+		files[fmt.Sprintf("output/output%d.txt", testIndex)] = test.Answer
+		fmt.Fprintf(&gen, "%s\n", strings.ReplaceAll(t.getTestToBeWrittenFname(id), "/", "_"))
+		testIndex++
+	}
+	if len(subtasks) == 0 {
+		for _, test := range t.GetTestsSortedByID() {
+			writeTest(test.ID)
+		}
+		if len(t.tests) > 0 {
+			report.dropped("task has no test groups, exported with score type Sum of %g points per test", 100/float64(len(t.tests)))
+		}
+	}
+	for _, st := range subtasks {
+		fmt.Fprintf(&gen, "#ST: %d\n", st.points)
+		for _, id := range st.testIDs {
+			if st.public {
+				publicTests = append(publicTests, strconv.Itoa(testIndex))
+			}
+			writeTest(id)
+		}
+		params = append(params, []int{st.points, len(st.testIDs)})
+	}
+	files["gen/GEN"] = gen.Bytes()
+
+	taskYAML := cmsTaskYAML{
+		Name:            shortName,
+		Title:           t.taskName,
+		TimeLimit:       t.cpuTimeSeconds,
+		MemoryLimit:     t.memoryMegabytes,
+		NInput:          testIndex,
+		PublicTestcases: strings.Join(publicTests, ","),
+	}
+	if len(subtasks) > 0 {
+		taskYAML.ScoreType = "GroupMin"
+		taskYAML.ScoreTypeParameters = params
+	} else if len(t.tests) > 0 {
+		taskYAML.ScoreType = "Sum"
+		taskYAML.ScoreTypeParameters = 100 / float64(len(t.tests))
+	}
+
+	pdfLangs := make([]string, 0, len(t.pdfStatements))
+	for lang := range t.pdfStatements {
+		pdfLangs = append(pdfLangs, lang)
+	}
+	sort.Strings(pdfLangs)
+	primary := lookupLanguageCode(opts.PrimaryLanguage)
+	if primary == "" && len(pdfLangs) > 0 {
+		primary = pdfLangs[0]
+	}
+	for _, lang := range pdfLangs {
+		if lang == primary {
+			files["statement/statement.pdf"] = t.pdfStatements[lang]
+			taskYAML.PrimaryLanguage = lang
+		} else {
+			report.dropped("pdf statement in %s", lang)
+		}
+	}
+	if len(t.mdStatements) > 0 {
+		report.dropped("%d Markdown statements, CMS shows PDF statements only", len(t.mdStatements))
+	}
+
+	for i, e := range t.examples {
+		files[fmt.Sprintf("att/input%d.txt", i)] = e.Input
+		files[fmt.Sprintf("att/output%d.txt", i)] = e.Output
+		if len(e.MdNote) > 0 {
+			report.dropped("note of example %d", i+1)
+		}
+	}
+
+	if t.checker != nil {
+		files["check/checker.cpp"] = t.checker
+		report.dropped("checker is exported as source, compile it to check/checker before importing into CMS")
+	}
+
+	yamlContent, err := encodeYAML(taskYAML)
+	if err != nil {
+		return nil, err
+	}
+	files["task.yaml"] = yamlContent
+
+	err = writeFiles(dirPath, files)
+	if err != nil {
+		log.Printf("Error writing CMS task: %v\n", err)
+		return nil, err
+	}
+	return report, nil
+}
+
+// writeFiles writes files given by slash separated paths relative to dirPath
+func writeFiles(dirPath string, files map[string][]byte) error {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		p := filepath.Join(dirPath, filepath.FromSlash(name))
+		err := os.MkdirAll(filepath.Dir(p), 0755)
+		if err != nil {
+			return fmt.Errorf("error creating directory: %w", err)
+		}
+		err = os.WriteFile(p, files[name], 0644)
+		if err != nil {
+			return fmt.Errorf("error writing %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// ImportCMS reads a task in the CMS Italian YAML format, either a directory
+// or a zip archive. Subtasks come from "#ST:" lines of gen/GEN or else from
+// GroupMin score_type_parameters, each becomes a test group.
+func ImportCMS(packagePath string) (*Task, *ImportReport, error) {
+	fsys, closePkg, err := openPackage(packagePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closePkg()
+
+	return ImportCMSFS(fsys)
+}
+
+// ImportCMSFS reads a task in the CMS Italian YAML format rooted at fsys.
+func ImportCMSFS(fsys fs.FS) (*Task, *ImportReport, error) {
+	report := &ImportReport{Format: "cms"}
+
+	content, err := fs.ReadFile(fsys, "task.yaml")
+	if err != nil {
+		log.Printf("Error reading task.yaml: %v\n", err)
+		return nil, nil, fmt.Errorf("error reading task.yaml: %w", err)
+	}
+	var ty cmsTaskYAML
+	err = yaml.Unmarshal(content, &ty)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error parsing task.yaml: %w", err)
+	}
+	var keys map[string]any
+	err = yaml.Unmarshal(content, &keys)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error parsing task.yaml: %w", err)
+	}
+	known := map[string]bool{"name": true, "title": true, "time_limit": true, "memory_limit": true, "n_input": true,
+		"score_type": true, "score_type_parameters": true, "public_testcases": true, "infile": true, "outfile": true,
+		"primary_language": true}
+	unknownKeys := make([]string, 0)
+	for key := range keys {
+		if !known[key] && !cmsKnownKeys[key] {
+			unknownKeys = append(unknownKeys, key)
+		}
+	}
+	sort.Strings(unknownKeys)
+	for _, key := range unknownKeys {
+		report.unmapped("task.yaml key %s", key)
+	}
+
+	name := ty.Title
+	if name == "" {
+		name = ty.Name
+	}
+	t, err := NewTask(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	t.cpuTimeSeconds = ty.TimeLimit
+	t.memoryMegabytes = ty.MemoryLimit
+	if ty.Infile != "" || ty.Outfile != "" {
+		report.unmapped("file input/output (%q, %q), tests use standard input and output", ty.Infile, ty.Outfile)
+	}
+
+	n := ty.NInput
+	if n == 0 {
+		for fileExistsFS(fsys, fmt.Sprintf("input/input%d.txt", n)) {
+			n++
+		}
+	}
+	testIDs := make([]int, 0, n)
+	for i := 0; i < n; i++ {
+		input, err := fs.ReadFile(fsys, fmt.Sprintf("input/input%d.txt", i))
+		if err != nil {
+			return nil, nil, fmt.Errorf("error reading test %d input: %w", i, err)
+		}
+		answer, err := fs.ReadFile(fsys, fmt.Sprintf("output/output%d.txt", i))
+		if err != nil {
+			return nil, nil, fmt.Errorf("error reading test %d output: %w", i, err)
+		}
+		testIDs = append(testIDs, t.AddTest(input, answer))
+	}
+
+	subtasks, names, err := readCMSGen(fsys, n)
+	if err != nil {
+		return nil, nil, err
+	}
+	if subtasks == nil {
+		subtasks, err = cmsSubtasksFromParameters(ty, n, report)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	taken := make(map[string]bool)
+	for i, name := range names {
+		if name == "" || taken[name] {
+			continue
+		}
+		taken[name] = true
+		t.AssignFilenameToTest(name, testIDs[i])
+	}
+
+	public := make(map[int]bool)
+	if ty.PublicTestcases == "all" {
+		for i := 0; i < n; i++ {
+			public[i] = true
+		}
+	} else if ty.PublicTestcases != "" {
+		for _, s := range strings.Split(ty.PublicTestcases, ",") {
+			i, err := strconv.Atoi(strings.TrimSpace(s))
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid public_testcases %q", ty.PublicTestcases)
+			}
+			public[i] = true
+		}
+	}
+
+	// a leading 0 point subtask holds the examples and is numbered 0
+	firstSubtask := 1
+	if len(subtasks) > 0 && subtasks[0].points == 0 {
+		firstSubtask = 0
+	}
+	for i, st := range subtasks {
+		if st.points != float64(int(st.points)) {
+			report.unmapped("subtask %d fractional points %g rounded down", i+1, st.points)
+		}
+		ids := make([]int, 0, len(st.tests))
+		allPublic := len(st.tests) > 0
+		for _, idx := range st.tests {
+			ids = append(ids, testIDs[idx])
+			allPublic = allPublic && public[idx]
+		}
+		err = t.AddTestGroupWithID(i+1, int(st.points), allPublic, ids, firstSubtask+i)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	err = t.importCMSStatements(fsys, ty, report)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i := 0; ; i++ {
+		input, err1 := fs.ReadFile(fsys, fmt.Sprintf("att/input%d.txt", i))
+		output, err2 := fs.ReadFile(fsys, fmt.Sprintf("att/output%d.txt", i))
+		if err1 != nil || err2 != nil {
+			break
+		}
+		t.AddExample(input, output, nil)
+	}
+
+	for _, p := range []string{"check/checker.cpp", "cor/correttore.cpp"} {
+		if source, err := fs.ReadFile(fsys, p); err == nil {
+			t.checker = source
+			break
+		}
+	}
+	if t.checker == nil {
+		for _, p := range []string{"check/checker", "cor/correttore"} {
+			if fileExistsFS(fsys, p) {
+				report.unmapped("checker %s without source", p)
+			}
+		}
+	}
+	for _, dir := range []string{"sol", "gen", "check", "cor", "att"} {
+		entries, err := fs.ReadDir(fsys, dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			p := path.Join(dir, e.Name())
+			switch {
+			case p == "gen/GEN" || p == "check/checker.cpp" || p == "cor/correttore.cpp":
+			case p == "check/checker" || p == "cor/correttore":
+			case dir == "att" && cmsAttExampleRegexp.MatchString(e.Name()):
+			default:
+				report.unmapped("file %s", p)
+			}
+		}
+	}
+
+	return t, report, nil
+}
+
+var cmsAttExampleRegexp = regexp.MustCompile(`^(input|output)[0-9]+\.txt$`)
+
+type cmsSubtask struct {
+	points float64
+	tests  []int // test indices
+}
+
+// readCMSGen returns the subtasks given by "#ST:" lines of gen/GEN and the
+// names of the tests, nil subtasks if there is no GEN file or it has no
+// subtask lines.
+func readCMSGen(fsys fs.FS, n int) ([]cmsSubtask, []string, error) {
+	names := make([]string, n)
+	content, err := fs.ReadFile(fsys, "gen/GEN")
+	if err != nil {
+		return nil, names, nil
+	}
+
+	subtasks := make([]cmsSubtask, 0)
+	idx := 0
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if m := cmsSubtaskLineRegexp.FindStringSubmatch(line); m != nil {
+			points, err := strconv.ParseFloat(m[1], 64)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid GEN subtask line %q", line)
+			}
+			subtasks = append(subtasks, cmsSubtask{points: points})
+			continue
+		}
+
+		name := ""
+		switch {
+		case strings.HasPrefix(line, "#COPY:"):
+			name = path.Base(strings.TrimSpace(line[len("#COPY:"):]))
+			name = strings.TrimSuffix(name, path.Ext(name))
+		case line == "" || strings.HasPrefix(line, "#"):
+			continue
+		default:
+			if fields := strings.Fields(line); len(fields) == 1 && cmsTestNameRegexp.MatchString(fields[0]) {
+				name = fields[0]
+			}
+		}
+
+		if idx >= n {
+			return nil, nil, fmt.Errorf("gen/GEN lists more than %d tests", n)
+		}
+		if len(subtasks) > 0 {
+			subtasks[len(subtasks)-1].tests = append(subtasks[len(subtasks)-1].tests, idx)
+		}
+		names[idx] = name
+		idx++
+	}
+	if idx != n {
+		return nil, nil, fmt.Errorf("gen/GEN lists %d tests, expected %d", idx, n)
+	}
+	if len(subtasks) == 0 {
+		return nil, names, nil
+	}
+	return subtasks, names, nil
+}
+
+// cmsSubtasksFromParameters reads GroupMin and GroupMul score_type_parameters
+// given as [points, test count] or [points, codename regex] pairs.
+func cmsSubtasksFromParameters(ty cmsTaskYAML, n int, report *ImportReport) ([]cmsSubtask, error) {
+	if ty.ScoreType != "GroupMin" && ty.ScoreType != "GroupMul" {
+		if ty.ScoreType != "" {
+			report.unmapped("score type %s with parameters %v", ty.ScoreType, ty.ScoreTypeParameters)
+		}
+		return nil, nil
+	}
+	if ty.ScoreType == "GroupMul" {
+		report.unmapped("score type GroupMul imported as GroupMin")
+	}
+
+	params, ok := ty.ScoreTypeParameters.([]any)
+	if !ok {
+		return nil, fmt.Errorf("invalid score_type_parameters %v", ty.ScoreTypeParameters)
+	}
+	res := make([]cmsSubtask, 0, len(params))
+	next := 0
+	for _, p := range params {
+		pair, ok := p.([]any)
+		if !ok || len(pair) != 2 {
+			return nil, fmt.Errorf("invalid score_type_parameters entry %v", p)
+		}
+		points, ok := yamlNumber(pair[0])
+		if !ok {
+			return nil, fmt.Errorf("invalid subtask points %v", pair[0])
+		}
+		st := cmsSubtask{points: points}
+		switch v := pair[1].(type) {
+		case int:
+			for i := 0; i < v; i++ {
+				st.tests = append(st.tests, next)
+				next++
+			}
+		case string:
+			re, err := regexp.Compile("^(?:" + v + ")$")
+			if err != nil {
+				return nil, fmt.Errorf("invalid subtask regex %q: %w", v, err)
+			}
+			for i := 0; i < n; i++ {
+				if re.MatchString(fmt.Sprintf("%03d", i)) {
+					st.tests = append(st.tests, i)
+				}
+			}
+		default:
+			return nil, fmt.Errorf("invalid subtask tests %v", pair[1])
+		}
+		res = append(res, st)
+	}
+	if next > n {
+		return nil, fmt.Errorf("score_type_parameters list %d tests, found %d", next, n)
+	}
+	return res, nil
+}
+
+func yamlNumber(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+func (t *Task) importCMSStatements(fsys fs.FS, ty cmsTaskYAML, report *ImportReport) error {
+	lang := ty.PrimaryLanguage
+	if lang == "" {
+		lang = "it"
+	}
+	for _, p := range []string{"statement/statement.pdf", "testo/testo.pdf"} {
+		pdf, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			continue
+		}
+		err = t.ReplacePDFStatement(lang, pdf)
+		if err != nil {
+			return err
+		}
+		break
+	}
+
+	for _, dir := range []string{"statement", "testo"} {
+		entries, err := fs.ReadDir(fsys, dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			if e.Name() != dir+".pdf" {
+				report.unmapped("file %s/%s", dir, e.Name())
+			}
+		}
+	}
+	return nil
+}
+
+func fileExistsFS(fsys fs.FS, name string) bool {
+	_, err := fs.Stat(fsys, name)
+	return err == nil
+}
@@ -0,0 +1,122 @@
+package fstaskparser_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/programme-lv/fs-task-format-parser/pkg/fstaskparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var cmsTaskPath = filepath.Join(prjRootPath, "testdata", "cms-aplusb")
+
+func TestImportingCMSTask(t *testing.T) {
+	task, report, err := fstaskparser.ImportCMS(cmsTaskPath)
+	require.NoErrorf(t, err, "failed to import task: %v", err)
+
+	assert.Equal(t, "A plus B", task.GetTaskName())
+	assert.Equal(t, 0.5, task.GetCPUTimeLimitInSeconds())
+	assert.Equal(t, 256, task.GetMemoryLimitInMegabytes())
+
+	tests := task.GetTestsSortedByID()
+	require.Equal(t, 5, len(tests))
+	assert.Equal(t, []byte("1000000000 1000000000\n"), tests[3].Input)
+	assert.Equal(t, []byte("2000000000\n"), tests[3].Answer)
+
+	require.Equal(t, []int{1, 2, 3}, task.GetTestGroupIDs())
+	assert.Equal(t, fstaskparser.TestGroupInfo{GroupID: 1, Points: 0, Public: true, TestIDs: []int{1}, Subtask: 0}, task.GetInfoOnTestGroup(1))
+	assert.Equal(t, fstaskparser.TestGroupInfo{GroupID: 2, Points: 40, Public: false, TestIDs: []int{2, 3}, Subtask: 1}, task.GetInfoOnTestGroup(2))
+	assert.Equal(t, fstaskparser.TestGroupInfo{GroupID: 3, Points: 60, Public: false, TestIDs: []int{4, 5}, Subtask: 2}, task.GetInfoOnTestGroup(3))
+
+	examples := task.GetExamples()
+	require.Equal(t, 1, len(examples))
+	assert.Equal(t, []byte("1 2\n"), examples[0].Input)
+	assert.Equal(t, []byte("3\n"), examples[0].Output)
+
+	pdf, err := task.GetPDFStatement("it")
+	require.NoErrorf(t, err, "failed to get pdf statement: %v", err)
+	assert.Equal(t, []byte("%PDF-1.4\n% A plus B\n%%EOF\n"), pdf)
+
+	assert.Contains(t, string(task.GetCheckerSource()), "Wrong answer")
+
+	assert.Equal(t, []string{
+		"task.yaml key output_only",
+		"file sol/aplusb.cpp",
+	}, report.Unmapped)
+
+	tmpDirectory, err := os.MkdirTemp("", "fstaskparser-test-")
+	require.NoErrorf(t, err, "failed to create temporary directory: %v", err)
+	defer os.RemoveAll(tmpDirectory)
+
+	// test data, attachments, statement and checker are written back unchanged
+	outputDirectory := filepath.Join(tmpDirectory, "aplusb")
+	_, err = task.ExportCMS(outputDirectory, fstaskparser.CMSExportOptions{})
+	require.NoErrorf(t, err, "failed to export task: %v", err)
+	for _, name := range []string{
+		"input/input0.txt", "input/input4.txt", "output/output2.txt",
+		"att/input0.txt", "att/output0.txt",
+		"statement/statement.pdf", "check/checker.cpp",
+	} {
+		expected, err := os.ReadFile(filepath.Join(cmsTaskPath, name))
+		require.NoErrorf(t, err, "failed to read fixture file: %v", err)
+		actual, err := os.ReadFile(filepath.Join(outputDirectory, name))
+		require.NoErrorf(t, err, "failed to read exported file: %v", err)
+		assert.Equalf(t, expected, actual, "exported %s differs", name)
+	}
+
+	reimported, _, err := fstaskparser.ImportCMS(outputDirectory)
+	require.NoErrorf(t, err, "failed to import exported task: %v", err)
+	assertSameCMSTask(t, task, reimported)
+	assert.Equal(t, []byte("1 2\n"), reimported.GetTestsSortedByID()[0].Input)
+}
+
+func TestExportingTaskToCMS(t *testing.T) {
+	task, err := fstaskparser.Read(testTaskPath)
+	require.NoErrorf(t, err, "failed to read task: %v", err)
+
+	tmpDirectory, err := os.MkdirTemp("", "fstaskparser-test-")
+	require.NoErrorf(t, err, "failed to create temporary directory: %v", err)
+	defer os.RemoveAll(tmpDirectory)
+
+	outputDirectory := filepath.Join(tmpDirectory, "kvadrputekl")
+	report, err := task.ExportCMS(outputDirectory, fstaskparser.CMSExportOptions{})
+	require.NoErrorf(t, err, "failed to export task: %v", err)
+	assert.Contains(t, report.Dropped, "1 Markdown statements, CMS shows PDF statements only")
+
+	taskYAML, err := os.ReadFile(filepath.Join(outputDirectory, "task.yaml"))
+	require.NoErrorf(t, err, "failed to read task.yaml: %v", err)
+	assert.Contains(t, string(taskYAML), "score_type: GroupMin\n")
+	assert.Contains(t, string(taskYAML), "primary_language: lv\n")
+
+	gen, err := os.ReadFile(filepath.Join(outputDirectory, "gen", "GEN"))
+	require.NoErrorf(t, err, "failed to read GEN: %v", err)
+	assert.Contains(t, string(gen), "#ST: 3\nkp01a\nkp01b\nkp01c\n#ST: 8\nkp02a\n")
+
+	imported, _, err := fstaskparser.ImportCMS(outputDirectory)
+	require.NoErrorf(t, err, "failed to import exported task: %v", err)
+	assertSameCMSTask(t, task, imported)
+
+	_, err = task.ExportCMS(outputDirectory, fstaskparser.CMSExportOptions{})
+	require.Error(t, err)
+}
+
+// assertSameCMSTask compares the parts of two tasks that the CMS format can hold
+func assertSameCMSTask(t *testing.T, expected *fstaskparser.Task, actual *fstaskparser.Task) {
+	assert.Equal(t, expected.GetTaskName(), actual.GetTaskName())
+	assert.Equal(t, expected.GetCPUTimeLimitInSeconds(), actual.GetCPUTimeLimitInSeconds())
+	assert.Equal(t, expected.GetMemoryLimitInMegabytes(), actual.GetMemoryLimitInMegabytes())
+	assert.Equal(t, expected.GetTestsSortedByID(), actual.GetTestsSortedByID())
+	assert.Equal(t, expected.GetTestGroupIDs(), actual.GetTestGroupIDs())
+	for _, id := range expected.GetTestGroupIDs() {
+		assert.Equal(t, expected.GetInfoOnTestGroup(id), actual.GetInfoOnTestGroup(id))
+	}
+	assert.Equal(t, expected.GetAllPDFStatements(), actual.GetAllPDFStatements())
+	assert.Equal(t, expected.GetCheckerSource(), actual.GetCheckerSource())
+	assert.Equal(t, len(expected.GetExamples()), len(actual.GetExamples()))
+	for i, e := range expected.GetExamples() {
+		assert.Equal(t, e.Input, actual.GetExamples()[i].Input)
+		assert.Equal(t, e.Output, actual.GetExamples()[i].Output)
+	}
+}
@@ -0,0 +1,153 @@
+package fstaskparser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// LanguageCoverage lists what a task provides in one statement language.
+type LanguageCoverage struct {
+	Language    string
+	MDSections  []string // present Markdown statement sections: "story", "input", "output", "notes", "scoring", then additional sections
+	PDF         bool
+	OriginNotes bool
+
+	// ExampleNotes is the number of examples with a note. Example notes are
+	// not translated, so they count for every language with a Markdown statement.
+	ExampleNotes int
+
+	// Missing lists what another language has but this one does not,
+	// e.g. "md/scoring", "pdf" or "origin_notes".
+	Missing []string
+}
+
+// Complete reports whether the language has a statement and lacks nothing
+// that another language has.
+func (c LanguageCoverage) Complete() bool {
+	return (len(c.MDSections) > 0 || c.PDF) && len(c.Missing) == 0
+}
+
+var mdSectionNames = []string{"story", "input", "output", "notes", "scoring"}
+
+// LanguageCoverage reports, for every language that has a Markdown statement,
+// a PDF statement or origin notes, which of them exist. Languages are sorted.
+func (t *Task) LanguageCoverage() []LanguageCoverage {
+	coverage := make(map[string]*LanguageCoverage)
+	get := func(lang string) *LanguageCoverage {
+		if c, ok := coverage[lang]; ok {
+			return c
+		}
+		c := &LanguageCoverage{Language: lang, MDSections: []string{}, Missing: []string{}}
+		coverage[lang] = c
+		return c
+	}
+
+	extraSections := make(map[string]string)
+	exampleNotes := 0
+	for _, e := range t.examples {
+		if len(strings.TrimSpace(string(e.MdNote))) > 0 {
+			exampleNotes++
+		}
+	}
+
+	for _, st := range t.mdStatements {
+		if st.Language == nil {
+			continue
+		}
+		c := get(*st.Language)
+		present := map[string]bool{
+			"story":   strings.TrimSpace(st.Story) != "",
+			"input":   strings.TrimSpace(st.Input) != "",
+			"output":  strings.TrimSpace(st.Output) != "",
+			"notes":   st.Notes != nil,
+			"scoring": st.Scoring != nil,
+		}
+		for _, section := range mdSectionNames {
+			if present[section] {
+				c.MDSections = append(c.MDSections, section)
+			}
+		}
+		for _, name := range sortedSectionNames(st.Sections) {
+			c.MDSections = append(c.MDSections, name)
+			extraSections[name] = ""
+		}
+		c.ExampleNotes = exampleNotes
+	}
+	for lang := range t.pdfStatements {
+		get(lang).PDF = true
+	}
+	for lang := range t.OriginNotes {
+		get(lang).OriginNotes = true
+	}
+
+	// an item is missing if any other language has it
+	has := func(c *LanguageCoverage) map[string]bool {
+		res := make(map[string]bool)
+		for _, section := range c.MDSections {
+			res["md/"+section] = true
+		}
+		res["pdf"] = c.PDF
+		res["origin_notes"] = c.OriginNotes
+		return res
+	}
+	all := make(map[string]bool)
+	for _, c := range coverage {
+		for item, ok := range has(c) {
+			all[item] = all[item] || ok
+		}
+	}
+	items := make([]string, 0)
+	for _, section := range mdSectionNames {
+		items = append(items, "md/"+section)
+	}
+	for _, name := range sortedSectionNames(extraSections) {
+		items = append(items, "md/"+name)
+	}
+	items = append(items, "pdf", "origin_notes")
+
+	res := make([]LanguageCoverage, 0, len(coverage))
+	for _, c := range coverage {
+		own := has(c)
+		for _, item := range items {
+			if all[item] && !own[item] {
+				c.Missing = append(c.Missing, item)
+			}
+		}
+		res = append(res, *c)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Language < res[j].Language
+	})
+	return res
+}
+
+// CheckRequiredLanguages returns an error unless every required language
+// has a statement that is as complete as the other languages. The required
+// languages must be valid BCP 47 tags, they are compared normalized.
+func (t *Task) CheckRequiredLanguages(required []string) error {
+	coverage := make(map[string]LanguageCoverage)
+	for _, c := range t.LanguageCoverage() {
+		coverage[c.Language] = c
+	}
+
+	problems := make([]string, 0)
+	for _, lang := range required {
+		lang, err := normalizeLanguageCode(lang)
+		if err != nil {
+			return fmt.Errorf("required language: %w", err)
+		}
+		c, ok := coverage[lang]
+		switch {
+		case !ok || (len(c.MDSections) == 0 && !c.PDF):
+			problems = append(problems, fmt.Sprintf("%s: no statement", lang))
+		case len(c.Missing) > 0:
+			problems = append(problems, fmt.Sprintf("%s: missing %s", lang, strings.Join(c.Missing, ", ")))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("incomplete required languages: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package fstaskparser_test
+
+import (
+	"testing"
+
+	"github.com/programme-lv/fs-task-format-parser/pkg/fstaskparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportingLanguageCoverage(t *testing.T) {
+	parsedTask, err := fstaskparser.Read(testTaskPath)
+	require.NoErrorf(t, err, "failed to read task: %v", err)
+
+	coverage := parsedTask.LanguageCoverage()
+	require.Equal(t, 1, len(coverage))
+	assert.Equal(t, "lv", coverage[0].Language)
+	assert.Equal(t, []string{"story", "input", "output", "scoring"}, coverage[0].MDSections)
+	assert.True(t, coverage[0].PDF)
+	assert.True(t, coverage[0].OriginNotes)
+	assert.Equal(t, 1, coverage[0].ExampleNotes)
+	assert.Empty(t, coverage[0].Missing)
+	assert.True(t, coverage[0].Complete())
+
+	en := "en"
+	statements := parsedTask.GetMarkdownStatements()
+	statements = append(statements, fstaskparser.MarkdownStatement{
+		Language: &en,
+		Story:    "Story",
+		Input:    "Input",
+		Output:   "Output",
+	})
+	parsedTask.SetMarkdownStatements(statements)
+
+	coverage = parsedTask.LanguageCoverage()
+	require.Equal(t, 2, len(coverage))
+	assert.Equal(t, "en", coverage[0].Language)
+	assert.Equal(t, []string{"story", "input", "output"}, coverage[0].MDSections)
+	assert.False(t, coverage[0].PDF)
+	assert.Equal(t, []string{"md/scoring", "pdf", "origin_notes"}, coverage[0].Missing)
+	assert.False(t, coverage[0].Complete())
+	assert.Empty(t, coverage[1].Missing)
+
+	require.NoError(t, parsedTask.CheckRequiredLanguages([]string{"lv"}))
+	require.NoError(t, parsedTask.CheckRequiredLanguages([]string{"LV"}))
+	require.Error(t, parsedTask.CheckRequiredLanguages([]string{"?"}))
+	err = parsedTask.CheckRequiredLanguages([]string{"lv", "en", "lt"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "en: missing md/scoring, pdf, origin_notes")
+	assert.Contains(t, err.Error(), "lt: no statement")
+
+	_, err = fstaskparser.ReadWithOptions(testTaskPath, fstaskparser.ReadOptions{RequiredLanguages: []string{"lv", "en"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "error checking required statement languages: incomplete required languages: en: no statement")
+	_, err = fstaskparser.ReadWithOptions(testTaskPath, fstaskparser.ReadOptions{RequiredLanguages: []string{"lv"}})
+	require.NoError(t, err)
+}
@@ -0,0 +1,430 @@
+package fstaskparser
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// DiffChange tells how a part of the task changed.
+type DiffChange string
+
+const (
+	DiffAdded    DiffChange = "added"
+	DiffRemoved  DiffChange = "removed"
+	DiffModified DiffChange = "modified"
+	DiffRenamed  DiffChange = "renamed"
+)
+
+// TaskDiff lists the differences between two versions of a task.
+// Tests are matched by their filename, the "%03d" ID if they have none, and
+// tests left without a match are paired by their input and answer.
+type TaskDiff struct {
+	Limits     []LimitDiff     `json:"limits"`
+	Tests      []TestDiff      `json:"tests"`
+	Groups     []GroupDiff     `json:"groups"`
+	MovedTests []TestMove      `json:"moved_tests"`
+	Statements []StatementDiff `json:"statements"`
+}
+
+// LimitDiff is a changed limit, "cpu_time_seconds" or "memory_megabytes".
+type LimitDiff struct {
+	Limit string  `json:"limit"`
+	Old   float64 `json:"old"`
+	New   float64 `json:"new"`
+}
+
+// TestDiff is an added, removed, modified or renamed test. Tests are
+// compared by the SHA-256 of their input and answer, a renamed test has the
+// same contents under another name and its old name in OldTest.
+type TestDiff struct {
+	Test          string     `json:"test"`
+	OldTest       string     `json:"old_test,omitempty"`
+	Change        DiffChange `json:"change"`
+	InputChanged  bool       `json:"input_changed,omitempty"`
+	AnswerChanged bool       `json:"answer_changed,omitempty"`
+}
+
+// GroupDiff is a test group whose points changed. An added or removed
+// group has 0 points on the side where it is missing.
+type GroupDiff struct {
+	GroupID   int        `json:"group_id"`
+	Change    DiffChange `json:"change"`
+	OldPoints int        `json:"old_points"`
+	NewPoints int        `json:"new_points"`
+}
+
+// TestMove is a test of both versions that belongs to other test groups,
+// a renamed test is listed by its new name.
+type TestMove struct {
+	Test       string `json:"test"`
+	FromGroups []int  `json:"from_groups"`
+	ToGroups   []int  `json:"to_groups"`
+}
+
+// StatementDiff is a changed statement section of one language. Sections are
+// the Markdown sections "story", "input", "output", "notes", "scoring", the
+// additional sections, and "pdf" for the PDF statement.
+type StatementDiff struct {
+	Language string     `json:"language"`
+	Section  string     `json:"section"`
+	Change   DiffChange `json:"change"`
+}
+
+// Empty reports whether the tasks have none of the compared differences.
+func (d TaskDiff) Empty() bool {
+	return len(d.Limits) == 0 && len(d.Tests) == 0 && len(d.Groups) == 0 &&
+		len(d.MovedTests) == 0 && len(d.Statements) == 0
+}
+
+// Diff compares the limits, tests, test groups and statements of a and b.
+func Diff(a, b *Task) TaskDiff {
+	d := TaskDiff{
+		Limits:     []LimitDiff{},
+		Tests:      []TestDiff{},
+		Groups:     []GroupDiff{},
+		MovedTests: []TestMove{},
+		Statements: []StatementDiff{},
+	}
+
+	if a.cpuTimeSeconds != b.cpuTimeSeconds {
+		d.Limits = append(d.Limits, LimitDiff{Limit: "cpu_time_seconds", Old: a.cpuTimeSeconds, New: b.cpuTimeSeconds})
+	}
+	if a.memoryMegabytes != b.memoryMegabytes {
+		d.Limits = append(d.Limits, LimitDiff{Limit: "memory_megabytes", Old: float64(a.memoryMegabytes), New: float64(b.memoryMegabytes)})
+	}
+
+	pairs := pairDiffTests(a, b)
+	d.Tests = diffTests(pairs)
+	d.Groups = diffGroups(a, b)
+	d.MovedTests = diffTestGroupMembership(a, b, pairs)
+	d.Statements = diffStatements(a, b)
+	return d
+}
+
+// diffTest is a test by name with the hashes it is compared by
+type diffTest struct {
+	name   string
+	input  [sha256.Size]byte
+	answer [sha256.Size]byte
+}
+
+// contents is the key that tests of the same input and answer share
+func (dt diffTest) contents() [2][sha256.Size]byte {
+	return [2][sha256.Size]byte{dt.input, dt.answer}
+}
+
+// diffTestsOf lists the tests in ID order
+func diffTestsOf(t *Task) []diffTest {
+	tests := make([]diffTest, 0, len(t.tests))
+	for _, test := range t.GetTestsSortedByID() {
+		tests = append(tests, diffTest{
+			name:   t.getTestToBeWrittenFname(test.ID),
+			input:  sha256.Sum256(test.Input),
+			answer: sha256.Sum256(test.Answer),
+		})
+	}
+	return tests
+}
+
+// diffTestPair is a test of a and its match in b, either may be missing
+type diffTestPair struct {
+	a, b *diffTest
+}
+
+// pairDiffTests matches the tests of a and b. Tests of the same name and
+// contents are matched first, then the rest by contents, so that renamed and
+// renumbered tests are found, then the rest by name. The pairs follow the
+// order of a, the tests only in b come last.
+func pairDiffTests(a, b *Task) []diffTestPair {
+	aTests := diffTestsOf(a)
+	bTests := diffTestsOf(b)
+	bByName := make(map[string]int, len(bTests))
+	bByContents := make(map[[2][sha256.Size]byte][]int, len(bTests))
+	for j, bt := range bTests {
+		bByName[bt.name] = j
+		bByContents[bt.contents()] = append(bByContents[bt.contents()], j)
+	}
+
+	match := make([]int, len(aTests))
+	matched := make([]bool, len(bTests))
+	pair := func(i, j int) {
+		match[i] = j
+		matched[j] = true
+	}
+	for i, at := range aTests {
+		match[i] = -1
+		if j, ok := bByName[at.name]; ok && bTests[j].contents() == at.contents() {
+			pair(i, j)
+		}
+	}
+	for i, at := range aTests {
+		if match[i] != -1 {
+			continue
+		}
+		// tests of b with these contents, in ID order, drop the matched ones
+		js := bByContents[at.contents()]
+		for len(js) > 0 && matched[js[0]] {
+			js = js[1:]
+		}
+		if len(js) > 0 {
+			pair(i, js[0])
+			js = js[1:]
+		}
+		bByContents[at.contents()] = js
+	}
+	for i, at := range aTests {
+		if match[i] != -1 {
+			continue
+		}
+		if j, ok := bByName[at.name]; ok && !matched[j] {
+			pair(i, j)
+		}
+	}
+
+	res := make([]diffTestPair, 0, len(aTests)+len(bTests))
+	for i := range aTests {
+		p := diffTestPair{a: &aTests[i]}
+		if match[i] != -1 {
+			p.b = &bTests[match[i]]
+		}
+		res = append(res, p)
+	}
+	for j := range bTests {
+		if !matched[j] {
+			res = append(res, diffTestPair{b: &bTests[j]})
+		}
+	}
+	return res
+}
+
+func diffTests(pairs []diffTestPair) []TestDiff {
+	res := make([]TestDiff, 0)
+	for _, p := range pairs {
+		switch {
+		case p.b == nil:
+			res = append(res, TestDiff{Test: p.a.name, Change: DiffRemoved})
+		case p.a == nil:
+			res = append(res, TestDiff{Test: p.b.name, Change: DiffAdded})
+		case p.a.name != p.b.name:
+			res = append(res, TestDiff{Test: p.b.name, OldTest: p.a.name, Change: DiffRenamed})
+		case p.a.contents() != p.b.contents():
+			res = append(res, TestDiff{Test: p.a.name, Change: DiffModified,
+				InputChanged: p.a.input != p.b.input, AnswerChanged: p.a.answer != p.b.answer})
+		}
+	}
+	return res
+}
+
+func diffGroups(a, b *Task) []GroupDiff {
+	res := make([]GroupDiff, 0)
+	ids := make(map[int]bool)
+	aGroups := make(map[int]bool)
+	for _, id := range a.testGroupIDs {
+		ids[id] = true
+		aGroups[id] = true
+	}
+	bGroups := make(map[int]bool)
+	for _, id := range b.testGroupIDs {
+		ids[id] = true
+		bGroups[id] = true
+	}
+
+	for _, id := range sortedIntKeys(ids) {
+		g := GroupDiff{GroupID: id, OldPoints: a.tGroupPoints[id], NewPoints: b.tGroupPoints[id]}
+		switch {
+		case !bGroups[id]:
+			g.Change = DiffRemoved
+		case !aGroups[id]:
+			g.Change = DiffAdded
+		case g.OldPoints != g.NewPoints:
+			g.Change = DiffModified
+		default:
+			continue
+		}
+		res = append(res, g)
+	}
+	return res
+}
+
+// testGroupsByName maps test names to the sorted IDs of their groups
+func testGroupsByName(t *Task) map[string][]int {
+	res := make(map[string][]int)
+	for _, groupID := range t.testGroupIDs {
+		for _, testID := range t.tGroupTestIDs[groupID] {
+			name := t.getTestToBeWrittenFname(testID)
+			res[name] = append(res[name], groupID)
+		}
+	}
+	for _, groups := range res {
+		sort.Ints(groups)
+	}
+	return res
+}
+
+func diffTestGroupMembership(a, b *Task, pairs []diffTestPair) []TestMove {
+	res := make([]TestMove, 0)
+	aGroups := testGroupsByName(a)
+	bGroups := testGroupsByName(b)
+	for _, p := range pairs {
+		if p.a == nil || p.b == nil {
+			continue
+		}
+		from, to := aGroups[p.a.name], bGroups[p.b.name]
+		if !slices.Equal(from, to) {
+			res = append(res, TestMove{Test: p.b.name, FromGroups: orEmptyInts(from), ToGroups: orEmptyInts(to)})
+		}
+	}
+	return res
+}
+
+func orEmptyInts(s []int) []int {
+	if s == nil {
+		return []int{}
+	}
+	return s
+}
+
+// statementSectionsByLanguage maps languages to section names to contents,
+// a statement without a language is listed under ""
+func statementSectionsByLanguage(t *Task) map[string]map[string][]byte {
+	res := make(map[string]map[string][]byte)
+	get := func(lang string) map[string][]byte {
+		if res[lang] == nil {
+			res[lang] = make(map[string][]byte)
+		}
+		return res[lang]
+	}
+	for _, st := range t.mdStatements {
+		lang := ""
+		if st.Language != nil {
+			lang = *st.Language
+		}
+		sections := get(lang)
+		sections["story"] = []byte(st.Story)
+		sections["input"] = []byte(st.Input)
+		sections["output"] = []byte(st.Output)
+		if st.Notes != nil {
+			sections["notes"] = []byte(*st.Notes)
+		}
+		if st.Scoring != nil {
+			sections["scoring"] = []byte(*st.Scoring)
+		}
+		for name, content := range st.Sections {
+			sections[name] = []byte(content)
+		}
+	}
+	for lang, pdf := range t.pdfStatements {
+		get(lang)["pdf"] = pdf
+	}
+	return res
+}
+
+// diffSectionNames lists the Markdown sections in statement order, then the
+// additional sections sorted, then "pdf"
+func diffSectionNames(a, b map[string][]byte) []string {
+	extra := make(map[string]string)
+	for _, sections := range []map[string][]byte{a, b} {
+		for name := range sections {
+			if !coreStatementSections[name] && name != "pdf" {
+				extra[name] = ""
+			}
+		}
+	}
+	res := append([]string{}, mdSectionNames...)
+	res = append(res, sortedSectionNames(extra)...)
+	return append(res, "pdf")
+}
+
+func diffStatements(a, b *Task) []StatementDiff {
+	res := make([]StatementDiff, 0)
+	aLangs := statementSectionsByLanguage(a)
+	bLangs := statementSectionsByLanguage(b)
+	langs := make(map[string]string)
+	for lang := range aLangs {
+		langs[lang] = ""
+	}
+	for lang := range bLangs {
+		langs[lang] = ""
+	}
+
+	for _, lang := range sortedSectionNames(langs) {
+		aSections, bSections := aLangs[lang], bLangs[lang]
+		for _, name := range diffSectionNames(aSections, bSections) {
+			aContent, inA := aSections[name]
+			bContent, inB := bSections[name]
+			switch {
+			case inA && !inB:
+				res = append(res, StatementDiff{Language: lang, Section: name, Change: DiffRemoved})
+			case !inA && inB:
+				res = append(res, StatementDiff{Language: lang, Section: name, Change: DiffAdded})
+			case inA && inB && !bytes.Equal(aContent, bContent):
+				res = append(res, StatementDiff{Language: lang, Section: name, Change: DiffModified})
+			}
+		}
+	}
+	return res
+}
+
+// String renders the differences as readable text, one change per line.
+func (d TaskDiff) String() string {
+	if d.Empty() {
+		return "no differences"
+	}
+
+	var sb strings.Builder
+	sign := map[DiffChange]string{DiffAdded: "+", DiffRemoved: "-", DiffModified: "~", DiffRenamed: ">"}
+	if len(d.Limits) > 0 {
+		sb.WriteString("limits:\n")
+		for _, l := range d.Limits {
+			fmt.Fprintf(&sb, "  %s: %g -> %g\n", l.Limit, l.Old, l.New)
+		}
+	}
+	if len(d.Tests) > 0 {
+		sb.WriteString("tests:\n")
+		for _, test := range d.Tests {
+			if test.Change == DiffRenamed {
+				fmt.Fprintf(&sb, "  %s %s -> %s\n", sign[test.Change], test.OldTest, test.Test)
+				continue
+			}
+			fmt.Fprintf(&sb, "  %s %s", sign[test.Change], test.Test)
+			if test.Change == DiffModified {
+				parts := make([]string, 0, 2)
+				if test.InputChanged {
+					parts = append(parts, "input")
+				}
+				if test.AnswerChanged {
+					parts = append(parts, "answer")
+				}
+				fmt.Fprintf(&sb, " (%s)", strings.Join(parts, ", "))
+			}
+			sb.WriteString("\n")
+		}
+	}
+	if len(d.Groups) > 0 {
+		sb.WriteString("test groups:\n")
+		for _, g := range d.Groups {
+			fmt.Fprintf(&sb, "  %s group %d: %d -> %d points\n", sign[g.Change], g.GroupID, g.OldPoints, g.NewPoints)
+		}
+	}
+	if len(d.MovedTests) > 0 {
+		sb.WriteString("moved tests:\n")
+		for _, m := range d.MovedTests {
+			fmt.Fprintf(&sb, "  %s: groups %v -> %v\n", m.Test, m.FromGroups, m.ToGroups)
+		}
+	}
+	if len(d.Statements) > 0 {
+		sb.WriteString("statements:\n")
+		for _, st := range d.Statements {
+			lang := st.Language
+			if lang == "" {
+				lang = "-"
+			}
+			fmt.Fprintf(&sb, "  %s %s/%s\n", sign[st.Change], lang, st.Section)
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
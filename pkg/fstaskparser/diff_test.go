@@ -0,0 +1,116 @@
+package fstaskparser_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/programme-lv/fs-task-format-parser/pkg/fstaskparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffingTasks(t *testing.T) {
+	task, err := fstaskparser.Read(testTaskPath)
+	require.NoErrorf(t, err, "failed to read task: %v", err)
+	same, err := fstaskparser.Read(testTaskPath)
+	require.NoErrorf(t, err, "failed to read task: %v", err)
+	assert.True(t, fstaskparser.Diff(task, same).Empty())
+	assert.Equal(t, "no differences", fstaskparser.Diff(task, same).String())
+
+	a := newDiffTask(t, []string{"01a", "01b", "02a"}, []string{"1", "2", "3"})
+	require.NoError(t, a.AddTestGroupWithID(1, 10, false, []int{1, 2}, 1))
+	require.NoError(t, a.AddTestGroupWithID(2, 20, false, []int{3}, 2))
+	require.NoError(t, a.SetMarkdownStatement("lv", fstaskparser.MarkdownStatement{Story: "stāsts", Input: "ievade", Output: "izvade"}))
+
+	// 01b gets a new answer and moves to group 2, 02a is replaced by 02b
+	b := newDiffTask(t, []string{"01a", "01b", "02b"}, []string{"1", "20", "4"})
+	b.SetCPUTimeLimitInSeconds(0.5)
+	require.NoError(t, b.AddTestGroupWithID(1, 10, false, []int{1}, 1))
+	require.NoError(t, b.AddTestGroupWithID(2, 30, false, []int{2, 3}, 2))
+	require.NoError(t, b.SetMarkdownStatement("lv", fstaskparser.MarkdownStatement{Story: "cits stāsts", Input: "ievade", Output: "izvade"}))
+	require.NoError(t, b.SetMarkdownStatement("en", fstaskparser.MarkdownStatement{Story: "story", Input: "input", Output: "output"}))
+
+	d := fstaskparser.Diff(a, b)
+	assert.Equal(t, []fstaskparser.LimitDiff{{Limit: "cpu_time_seconds", Old: 1, New: 0.5}}, d.Limits)
+	assert.Equal(t, []fstaskparser.TestDiff{
+		{Test: "01b", Change: fstaskparser.DiffModified, AnswerChanged: true},
+		{Test: "02a", Change: fstaskparser.DiffRemoved},
+		{Test: "02b", Change: fstaskparser.DiffAdded},
+	}, d.Tests)
+	assert.Equal(t, []fstaskparser.GroupDiff{{GroupID: 2, Change: fstaskparser.DiffModified, OldPoints: 20, NewPoints: 30}}, d.Groups)
+	assert.Equal(t, []fstaskparser.TestMove{{Test: "01b", FromGroups: []int{1}, ToGroups: []int{2}}}, d.MovedTests)
+	assert.Equal(t, []fstaskparser.StatementDiff{
+		{Language: "en", Section: "story", Change: fstaskparser.DiffAdded},
+		{Language: "en", Section: "input", Change: fstaskparser.DiffAdded},
+		{Language: "en", Section: "output", Change: fstaskparser.DiffAdded},
+		{Language: "lv", Section: "story", Change: fstaskparser.DiffModified},
+	}, d.Statements)
+
+	assert.Equal(t, `limits:
+  cpu_time_seconds: 1 -> 0.5
+tests:
+  ~ 01b (answer)
+  - 02a
+  + 02b
+test groups:
+  ~ group 2: 20 -> 30 points
+moved tests:
+  01b: groups [1] -> [2]
+statements:
+  + en/story
+  + en/input
+  + en/output
+  ~ lv/story`, d.String())
+
+	content, err := json.Marshal(fstaskparser.Diff(a, a))
+	require.NoErrorf(t, err, "failed to marshal diff: %v", err)
+	assert.JSONEq(t, `{"limits": [], "tests": [], "groups": [], "moved_tests": [], "statements": []}`, string(content))
+}
+
+func TestDiffingRenamedTests(t *testing.T) {
+	a := newDiffTask(t, []string{"01a", "01b"}, []string{"1", "2"})
+	require.NoError(t, a.AddTestGroupWithID(1, 10, false, []int{1, 2}, 1))
+	b := newDiffTask(t, []string{"01a"}, []string{"1"})
+	id := b.AddTest([]byte("01b\n"), []byte("2\n"))
+	b.AssignFilenameToTest("02a", id)
+	require.NoError(t, b.AddTestGroupWithID(1, 10, false, []int{1}, 1))
+	require.NoError(t, b.AddTestGroupWithID(2, 0, false, []int{2}, 2))
+
+	d := fstaskparser.Diff(a, b)
+	assert.Equal(t, []fstaskparser.TestDiff{{Test: "02a", OldTest: "01b", Change: fstaskparser.DiffRenamed}}, d.Tests)
+	assert.Equal(t, []fstaskparser.TestMove{{Test: "02a", FromGroups: []int{1}, ToGroups: []int{2}}}, d.MovedTests)
+
+	// a test inserted before unnamed tests shifts their "%03d" names
+	unnamed := func(inputs ...string) *fstaskparser.Task {
+		task, err := fstaskparser.NewTask("diff")
+		require.NoErrorf(t, err, "failed to create task: %v", err)
+		for _, input := range inputs {
+			task.AddTest([]byte(input), []byte(input))
+		}
+		return task
+	}
+	d = fstaskparser.Diff(unnamed("a", "b"), unnamed("new", "a", "c"))
+	assert.Equal(t, `tests:
+  > 001 -> 002
+  - 002
+  + 001
+  + 003`, d.String())
+
+	// unchanged tests keep their name, the rest of the same contents are
+	// paired in ID order
+	d = fstaskparser.Diff(unnamed("a", "a", "b"), unnamed("b", "a", "a", "a"))
+	assert.Equal(t, `tests:
+  > 001 -> 003
+  > 003 -> 001
+  + 004`, d.String())
+}
+
+func newDiffTask(t *testing.T, names []string, answers []string) *fstaskparser.Task {
+	task, err := fstaskparser.NewTask("diff")
+	require.NoErrorf(t, err, "failed to create task: %v", err)
+	for i, name := range names {
+		id := task.AddTest([]byte(name+"\n"), []byte(answers[i]+"\n"))
+		task.AssignFilenameToTest(name, id)
+	}
+	return task
+}
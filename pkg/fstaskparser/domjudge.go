@@ -0,0 +1,260 @@
+package fstaskparser
+
+import (
+	"archive/zip"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DOMjudgeExportOptions control ExportDOMjudge.
+type DOMjudgeExportOptions struct {
+	// ShortName is the problem external id, the zip file name without extension if empty
+	ShortName string
+	// Language selects the PDF statement stored as problem.pdf,
+	// the alphabetically first PDF language if empty
+	Language string
+}
+
+// DOMjudgeWarnings lists the parts of the task that a DOMjudge problem
+// cannot express. DOMjudge judges pass-fail, so test group points, public
+// groups and visible input subtasks are lost on export.
+func (t *Task) DOMjudgeWarnings() []string {
+	res := make([]string, 0)
+	for _, groupID := range t.testGroupIDs {
+		if t.tGroupPoints[groupID] != 0 {
+			res = append(res, fmt.Sprintf("test group %d has %d points, DOMjudge judges pass-fail", groupID, t.tGroupPoints[groupID]))
+		}
+		if t.isTGroupPublic[groupID] {
+			res = append(res, fmt.Sprintf("test group %d is public, DOMjudge shows only the samples", groupID))
+		}
+	}
+	if len(t.visibleInputSubtasks) > 0 {
+		res = append(res, fmt.Sprintf("visible input subtasks %v", t.visibleInputSubtasks))
+	}
+	if len(t.pdfStatements) == 0 {
+		res = append(res, "no pdf statement, DOMjudge shows only pdf, html or txt statements")
+	} else if len(t.pdfStatements) > 1 {
+		res = append(res, "several pdf statements, DOMjudge keeps one")
+	}
+	if len(t.mdStatements) > 0 {
+		res = append(res, fmt.Sprintf("%d Markdown statements", len(t.mdStatements)))
+	}
+	for i, e := range t.examples {
+		if len(e.MdNote) > 0 {
+			res = append(res, fmt.Sprintf("note of example %d", i+1))
+		}
+	}
+	if len(t.editorials) > 0 {
+		res = append(res, fmt.Sprintf("%d editorials", len(t.editorials)))
+	}
+	if t.checker != nil {
+		res = append(res, "checker exported as output validator, it must follow the DOMjudge output validator interface")
+	}
+	return res
+}
+
+// ExportDOMjudge writes the task as a DOMjudge problem zip with
+// domjudge-problem.ini, problem.yaml, data/sample, data/secret and problem.pdf.
+// The report holds the DOMjudgeWarnings.
+func (t *Task) ExportDOMjudge(zipPath string, opts DOMjudgeExportOptions) (*ExportReport, error) {
+	report := &ExportReport{Format: "domjudge"}
+	for _, w := range t.DOMjudgeWarnings() {
+		report.dropped("%s", w)
+	}
+	if _, err := os.Stat(zipPath); !os.IsNotExist(err) {
+		return nil, fmt.Errorf("file already exists: %s", zipPath)
+	}
+
+	shortName := opts.ShortName
+	if shortName == "" {
+		shortName = strings.TrimSuffix(filepath.Base(zipPath), filepath.Ext(zipPath))
+	}
+
+	files := make(map[string][]byte)
+	var ini strings.Builder
+	fmt.Fprintf(&ini, "externalid = %s\n", domjudgeINIValue(shortName))
+	fmt.Fprintf(&ini, "name = %s\n", domjudgeINIValue(t.taskName))
+	fmt.Fprintf(&ini, "timelimit = %s\n", strconv.FormatFloat(t.cpuTimeSeconds, 'f', -1, 64))
+	files["domjudge-problem.ini"] = []byte(ini.String())
+
+	py := kattisProblemYAML{
+		Name:   t.taskName,
+		Limits: &kattisLimitsYAML{Memory: t.memoryMegabytes},
+	}
+	if t.checker != nil {
+		py.Validation = "custom"
+		files["output_validators/checker/checker.cpp"] = t.checker
+	}
+	content, err := encodeYAML(py)
+	if err != nil {
+		return nil, err
+	}
+	files["problem.yaml"] = content
+
+	for i, e := range t.examples {
+		name := fmt.Sprintf("%03d", i+1)
+		if e.Name != nil {
+			name = *e.Name
+		}
+		files["data/sample/"+name+".in"] = e.Input
+		files["data/sample/"+name+".ans"] = e.Output
+	}
+	for _, test := range t.GetTestsSortedByID() {
+		name := strings.ReplaceAll(t.getTestToBeWrittenFname(test.ID), "/", "_")
+		files["data/secret/"+name+".in"] = test.Input
+		files["data/secret/"+name+".ans"] = test.Answer
+	}
+
+	lang := lookupLanguageCode(opts.Language)
+	if lang == "" {
+		langs := make([]string, 0, len(t.pdfStatements))
+		for l := range t.pdfStatements {
+			langs = append(langs, l)
+		}
+		sort.Strings(langs)
+		if len(langs) > 0 {
+			lang = langs[0]
+		}
+	}
+	if lang != "" {
+		pdf, ok := t.pdfStatements[lang]
+		if !ok {
+			return nil, fmt.Errorf("pdf statement for language %s not found", lang)
+		}
+		files["problem.pdf"] = pdf
+	}
+
+	err = writeZip(zipPath, files)
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// domjudgeINIValue quotes a domjudge-problem.ini value
+func domjudgeINIValue(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "\\'") + "'"
+}
+
+// writeZip writes files given by slash separated paths into a new zip archive
+func writeZip(zipPath string, files map[string][]byte) error {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	f, err := os.Create(zipPath)
+	if err != nil {
+		return fmt.Errorf("error creating zip: %w", err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	for _, name := range names {
+		w, err := zw.Create(name)
+		if err != nil {
+			return fmt.Errorf("error adding %s to zip: %w", name, err)
+		}
+		_, err = w.Write(files[name])
+		if err != nil {
+			return fmt.Errorf("error writing %s to zip: %w", name, err)
+		}
+	}
+
+	err = zw.Close()
+	if err != nil {
+		return fmt.Errorf("error writing zip: %w", err)
+	}
+	return f.Close()
+}
+
+// ImportDOMjudge reads a DOMjudge problem zip or directory. It is a Kattis
+// package with domjudge-problem.ini and an optional problem.pdf on top.
+func ImportDOMjudge(packagePath string) (*Task, *ImportReport, error) {
+	fsys, closePkg, err := openPackage(packagePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closePkg()
+
+	return ImportDOMjudgeFS(fsys)
+}
+
+// ImportDOMjudgeFS reads a DOMjudge problem package rooted at fsys.
+func ImportDOMjudgeFS(fsys fs.FS) (*Task, *ImportReport, error) {
+	content, err := fs.ReadFile(fsys, "domjudge-problem.ini")
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading domjudge-problem.ini: %w", err)
+	}
+	ini, err := parseDOMjudgeINI(content)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	t, kattisReport, err := ImportKattisFS(fsys)
+	if err != nil {
+		return nil, nil, err
+	}
+	report := &ImportReport{Format: "domjudge"}
+
+	if name, ok := ini["name"]; ok && name != "" {
+		t.taskName = name
+	}
+	if tl, ok := ini["timelimit"]; ok {
+		t.cpuTimeSeconds, err = strconv.ParseFloat(tl, 64)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid timelimit in domjudge-problem.ini: %w", err)
+		}
+	}
+	for _, msg := range kattisReport.Unmapped {
+		// the time limit of DOMjudge is in domjudge-problem.ini
+		if t.cpuTimeSeconds > 0 && strings.HasPrefix(msg, "no time limit") {
+			continue
+		}
+		report.unmapped("%s", msg)
+	}
+	keys := make([]string, 0, len(ini))
+	for key := range ini {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if key != "name" && key != "timelimit" {
+			report.unmapped("domjudge-problem.ini key %s", key)
+		}
+	}
+
+	if pdf, err := fs.ReadFile(fsys, "problem.pdf"); err == nil {
+		// DOMjudge does not record the statement language
+		t.pdfStatements["en"] = pdf
+	}
+
+	return t, report, nil
+}
+
+// parseDOMjudgeINI reads the key = value lines of domjudge-problem.ini
+func parseDOMjudgeINI(content []byte) (map[string]string, error) {
+	res := make(map[string]string)
+	for i, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, ";") || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid line %d in domjudge-problem.ini: %s", i+1, line)
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '\'' || value[0] == '"') && value[len(value)-1] == value[0] {
+			value = strings.ReplaceAll(value[1:len(value)-1], "\\"+value[:1], value[:1])
+		}
+		res[strings.TrimSpace(key)] = value
+	}
+	return res, nil
+}
@@ -0,0 +1,66 @@
+package fstaskparser_test
+
+import (
+	"archive/zip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/programme-lv/fs-task-format-parser/pkg/fstaskparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportingTaskToDOMjudge(t *testing.T) {
+	task, err := fstaskparser.Read(testTaskPath)
+	require.NoErrorf(t, err, "failed to read task: %v", err)
+
+	tmpDirectory, err := os.MkdirTemp("", "fstaskparser-test-")
+	require.NoErrorf(t, err, "failed to create temporary directory: %v", err)
+	defer os.RemoveAll(tmpDirectory)
+
+	zipPath := filepath.Join(tmpDirectory, "kvadrputekl.zip")
+	report, err := task.ExportDOMjudge(zipPath, fstaskparser.DOMjudgeExportOptions{})
+	require.NoErrorf(t, err, "failed to export task: %v", err)
+	assert.Equal(t, task.DOMjudgeWarnings(), report.Dropped)
+	assert.Contains(t, report.Dropped, "test group 2 has 8 points, DOMjudge judges pass-fail")
+	assert.Contains(t, report.Dropped, "test group 1 is public, DOMjudge shows only the samples")
+
+	zr, err := zip.OpenReader(zipPath)
+	require.NoErrorf(t, err, "failed to open zip: %v", err)
+	defer zr.Close()
+
+	files := make(map[string][]byte)
+	for _, f := range zr.File {
+		r, err := f.Open()
+		require.NoErrorf(t, err, "failed to open %s: %v", f.Name, err)
+		files[f.Name], err = io.ReadAll(r)
+		require.NoErrorf(t, err, "failed to read %s: %v", f.Name, err)
+		r.Close()
+	}
+
+	assert.Equal(t, "externalid = 'kvadrputekl'\nname = 'Kvadrātveida putekļsūcējs'\ntimelimit = 0.5\n", string(files["domjudge-problem.ini"]))
+	assert.Equal(t, "name: Kvadrātveida putekļsūcējs\nlimits:\n  memory: 256\n", string(files["problem.yaml"]))
+	assert.Equal(t, task.GetTestsSortedByID()[3].Answer, files["data/secret/kp02a.ans"])
+	assert.Equal(t, task.GetExamples()[0].Input, files["data/sample/kp00.in"])
+
+	pdf, err := task.GetPDFStatement("lv")
+	require.NoErrorf(t, err, "failed to get pdf statement: %v", err)
+	assert.Equal(t, pdf, files["problem.pdf"])
+	assert.Equal(t, 2+4+12+1, len(files))
+
+	imported, importReport, err := fstaskparser.ImportDOMjudge(zipPath)
+	require.NoErrorf(t, err, "failed to import exported task: %v", err)
+	assert.Equal(t, task.GetTaskName(), imported.GetTaskName())
+	assert.Equal(t, task.GetCPUTimeLimitInSeconds(), imported.GetCPUTimeLimitInSeconds())
+	assert.Equal(t, task.GetMemoryLimitInMegabytes(), imported.GetMemoryLimitInMegabytes())
+	assert.Equal(t, len(task.GetTestsSortedByID()), len(imported.GetTestsSortedByID()))
+	importedPDF, err := imported.GetPDFStatement("en")
+	require.NoErrorf(t, err, "failed to get pdf statement: %v", err)
+	assert.Equal(t, pdf, importedPDF)
+	assert.Equal(t, []string{"domjudge-problem.ini key externalid"}, importReport.Unmapped)
+
+	_, err = task.ExportDOMjudge(zipPath, fstaskparser.DOMjudgeExportOptions{})
+	require.Error(t, err)
+}
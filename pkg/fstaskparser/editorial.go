@@ -0,0 +1,225 @@
+package fstaskparser
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// EditorialRelease tells when the editorials of a task may be published.
+type EditorialRelease string
+
+const (
+	// EditorialReleaseAfterContest editorials are published once the contest is over
+	EditorialReleaseAfterContest EditorialRelease = "after_contest"
+	// EditorialReleaseImmediately editorials are published together with the statement
+	EditorialReleaseImmediately EditorialRelease = "immediately"
+)
+
+// Editorial is the solution write-up of a task in one language, stored in
+// editorial/<lang>/ as editorial.md and/or editorial.pdf with images in
+// editorial/<lang>/assets/. Editorials are never part of contestant-facing
+// output unless explicitly requested.
+type Editorial struct {
+	Language string
+	Markdown *string
+	PDF      []byte
+	Assets   []Asset // paths relative to editorial/<lang>/assets/
+}
+
+type editorial struct {
+	Markdown *string
+	PDF      []byte
+	Assets   []asset
+}
+
+func validateEditorialRelease(r EditorialRelease) error {
+	if r != EditorialReleaseAfterContest && r != EditorialReleaseImmediately {
+		return fmt.Errorf("invalid editorial release %q", r)
+	}
+	return nil
+}
+
+func (t *Task) GetEditorialRelease() EditorialRelease {
+	return t.editorialRelease
+}
+
+func (t *Task) SetEditorialRelease(release EditorialRelease) error {
+	err := validateEditorialRelease(release)
+	if err != nil {
+		return err
+	}
+
+	t.editorialRelease = release
+	return nil
+}
+
+func (t *Task) GetEditorial(lang string) (Editorial, error) {
+	lang = lookupLanguageCode(lang)
+	e, ok := t.editorials[lang]
+	if !ok {
+		return Editorial{}, fmt.Errorf("editorial for language %s not found", lang)
+	}
+
+	return e.export(lang), nil
+}
+
+// GetAllEditorials returns the editorials sorted by language
+func (t *Task) GetAllEditorials() []Editorial {
+	langs := make([]string, 0, len(t.editorials))
+	for lang := range t.editorials {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+
+	res := make([]Editorial, 0, len(langs))
+	for _, lang := range langs {
+		res = append(res, t.editorials[lang].export(lang))
+	}
+	return res
+}
+
+// SetEditorial adds or replaces the editorial of a language.
+// The language must be a BCP 47 tag, e.Language is ignored.
+func (t *Task) SetEditorial(lang string, e Editorial) error {
+	lang, err := normalizeLanguageCode(lang)
+	if err != nil {
+		return err
+	}
+	if e.Markdown == nil && e.PDF == nil {
+		return fmt.Errorf("editorial for language %s has neither markdown nor pdf", lang)
+	}
+
+	assets := make([]asset, 0, len(e.Assets))
+	for _, a := range e.Assets {
+		err = validateRelativePath(a.RelativePath)
+		if err != nil {
+			return fmt.Errorf("editorial for language %s asset: %w", lang, err)
+		}
+		assets = append(assets, asset(a))
+	}
+	t.editorials[lang] = editorial{
+		Markdown: e.Markdown,
+		PDF:      e.PDF,
+		Assets:   assets,
+	}
+	return nil
+}
+
+func (t *Task) RemoveEditorial(lang string) error {
+	lang = lookupLanguageCode(lang)
+	if _, ok := t.editorials[lang]; !ok {
+		return fmt.Errorf("editorial for language %s not found", lang)
+	}
+
+	delete(t.editorials, lang)
+	return nil
+}
+
+func (e editorial) export(lang string) Editorial {
+	assets := make([]Asset, 0, len(e.Assets))
+	for _, a := range e.Assets {
+		assets = append(assets, Asset(a))
+	}
+	return Editorial{
+		Language: lang,
+		Markdown: e.Markdown,
+		PDF:      e.PDF,
+		Assets:   assets,
+	}
+}
+
+// readEditorials reads editorial/<lang>/ directories. Language directories
+// without editorial.md or editorial.pdf are skipped.
+func readEditorials(rootDirPath string) (map[string]editorial, error) {
+	res := make(map[string]editorial)
+	dirPath := filepath.Join(rootDirPath, "editorial")
+	if _, err := os.Stat(dirPath); os.IsNotExist(err) {
+		return res, nil
+	}
+
+	langs, err := os.ReadDir(dirPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading editorial directory: %w", err)
+	}
+
+	for _, lang := range langs {
+		if !lang.IsDir() {
+			continue
+		}
+		langDirPath := filepath.Join(dirPath, lang.Name())
+
+		e := editorial{}
+		md, err := os.ReadFile(filepath.Join(langDirPath, "editorial.md"))
+		if err == nil {
+			e.Markdown = &([]string{string(md)}[0])
+		} else if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("error reading editorial: %w", err)
+		}
+
+		pdf, err := os.ReadFile(filepath.Join(langDirPath, "editorial.pdf"))
+		if err == nil {
+			e.PDF = pdf
+		} else if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("error reading editorial: %w", err)
+		}
+
+		if e.Markdown == nil && e.PDF == nil {
+			log.Printf("Skipping editorial directory without editorial.md or editorial.pdf: %s\n", langDirPath)
+			continue
+		}
+
+		e.Assets, err = readAssets(langDirPath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading editorial assets: %w", err)
+		}
+
+		// valid tags are normalized, so that lookups find en-us as en-US
+		langStr := lookupLanguageCode(lang.Name())
+		if _, ok := res[langStr]; ok {
+			log.Printf("Skipping editorial directory of an already read language %s: %s\n", langStr, langDirPath)
+			continue
+		}
+		res[langStr] = e
+	}
+
+	return res, nil
+}
+
+func (task *Task) storeEditorials(editorialDir string) error {
+	for lang, e := range task.editorials {
+		langDirPath := filepath.Join(editorialDir, lang)
+		err := os.MkdirAll(langDirPath, 0755)
+		if err != nil {
+			log.Printf("Error creating editorial directory: %v\n", err)
+			return fmt.Errorf("error creating editorial directory: %w", err)
+		}
+
+		if e.Markdown != nil {
+			err = os.WriteFile(filepath.Join(langDirPath, "editorial.md"), []byte(*e.Markdown), 0644)
+			if err != nil {
+				log.Printf("Error writing editorial: %v\n", err)
+				return fmt.Errorf("error writing editorial: %w", err)
+			}
+		}
+
+		if e.PDF != nil {
+			err = os.WriteFile(filepath.Join(langDirPath, "editorial.pdf"), e.PDF, 0644)
+			if err != nil {
+				log.Printf("Error writing editorial: %v\n", err)
+				return fmt.Errorf("error writing editorial: %w", err)
+			}
+		}
+
+		if len(e.Assets) > 0 {
+			err = storeAssetFiles(filepath.Join(langDirPath, "assets"), e.Assets)
+			if err != nil {
+				return err
+			}
+		}
+		log.Printf("Editorial written: %s\n", langDirPath)
+	}
+	return nil
+}
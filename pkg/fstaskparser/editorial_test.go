@@ -0,0 +1,93 @@
+package fstaskparser_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/programme-lv/fs-task-format-parser/pkg/fstaskparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadingWritingEditorials(t *testing.T) {
+	parsedTask, err := fstaskparser.Read(testTaskPath)
+	require.NoErrorf(t, err, "failed to read task: %v", err)
+	assert.Empty(t, parsedTask.GetAllEditorials())
+	assert.Equal(t, fstaskparser.EditorialReleaseAfterContest, parsedTask.GetEditorialRelease())
+
+	md := "Risinājums ar prefiksu summām.\n\n![zīmējums](img/grid.png)\n"
+	err = parsedTask.SetEditorial("lv", fstaskparser.Editorial{
+		Markdown: &md,
+		Assets:   []fstaskparser.Asset{{RelativePath: "img/grid.png", Content: []byte("\x89PNG\r\n\x1a\n")}},
+	})
+	require.NoErrorf(t, err, "failed to set editorial: %v", err)
+	err = parsedTask.SetEditorial("en", fstaskparser.Editorial{PDF: []byte("%PDF")})
+	require.NoErrorf(t, err, "failed to set editorial: %v", err)
+	require.Error(t, parsedTask.SetEditorial("en", fstaskparser.Editorial{}))
+	require.Error(t, parsedTask.SetEditorial("?", fstaskparser.Editorial{PDF: []byte("%PDF")}))
+	require.Error(t, parsedTask.SetEditorial("en", fstaskparser.Editorial{
+		Markdown: &md,
+		Assets:   []fstaskparser.Asset{{RelativePath: "../grid.png", Content: []byte("\x89PNG\r\n\x1a\n")}},
+	}))
+	require.Error(t, parsedTask.SetEditorialRelease("never"))
+
+	// lookups normalize the tag like SetEditorial does
+	require.NoError(t, parsedTask.SetEditorial("en-us", fstaskparser.Editorial{PDF: []byte("%PDF")}))
+	enUS, err := parsedTask.GetEditorial("en-us")
+	require.NoErrorf(t, err, "failed to get editorial: %v", err)
+	assert.Equal(t, "en-US", enUS.Language)
+	require.NoError(t, parsedTask.RemoveEditorial("EN-us"))
+
+	// contestant-facing output leaves editorials out unless asked
+	rendered, err := parsedTask.RenderStatementHTML("lv", fstaskparser.RenderOptions{Fragment: true})
+	require.NoErrorf(t, err, "failed to render statement: %v", err)
+	assert.False(t, strings.Contains(string(rendered), "prefiksu summām"))
+	rendered, err = parsedTask.RenderStatementHTML("LV", fstaskparser.RenderOptions{Fragment: true, IncludeEditorial: true})
+	require.NoErrorf(t, err, "failed to render statement: %v", err)
+	assert.Contains(t, string(rendered), "<h2>Risinājuma apraksts</h2>")
+	assert.Contains(t, string(rendered), `src="data:image/png;base64,iVBORw0KGgo="`)
+
+	tmpDirectory, err := os.MkdirTemp("", "fstaskparser-test-")
+	require.NoErrorf(t, err, "failed to create temporary directory: %v", err)
+	defer os.RemoveAll(tmpDirectory)
+
+	outputDirectory := filepath.Join(tmpDirectory, "kvadrputekl")
+	err = parsedTask.Store(outputDirectory)
+	require.NoErrorf(t, err, "failed to store task: %v", err)
+
+	assert.FileExists(t, filepath.Join(outputDirectory, "editorial", "lv", "editorial.md"))
+	assert.FileExists(t, filepath.Join(outputDirectory, "editorial", "lv", "assets", "img", "grid.png"))
+	assert.FileExists(t, filepath.Join(outputDirectory, "editorial", "en", "editorial.pdf"))
+	pToml, err := os.ReadFile(filepath.Join(outputDirectory, "problem.toml"))
+	require.NoErrorf(t, err, "failed to read problem.toml: %v", err)
+	assert.Contains(t, string(pToml), "release = 'after_contest'")
+
+	storedTask, err := fstaskparser.Read(outputDirectory)
+	require.NoErrorf(t, err, "failed to read task: %v", err)
+	assert.Equal(t, parsedTask.GetAllEditorials(), storedTask.GetAllEditorials())
+
+	// editorial directories named by non-canonical tags are read normalized
+	err = os.Rename(filepath.Join(outputDirectory, "editorial", "lv"), filepath.Join(outputDirectory, "editorial", "LV"))
+	require.NoErrorf(t, err, "failed to rename directory: %v", err)
+	renamedTask, err := fstaskparser.Read(outputDirectory)
+	require.NoErrorf(t, err, "failed to read task: %v", err)
+	_, err = renamedTask.GetEditorial("lv")
+	require.NoErrorf(t, err, "failed to get editorial: %v", err)
+
+	require.NoError(t, storedTask.SetEditorialRelease(fstaskparser.EditorialReleaseImmediately))
+	require.NoError(t, storedTask.RemoveEditorial("en"))
+	require.Error(t, storedTask.RemoveEditorial("en"))
+
+	anotherOutputDirectory := filepath.Join(tmpDirectory, "kvadrputekl2")
+	err = storedTask.Store(anotherOutputDirectory)
+	require.NoErrorf(t, err, "failed to store task: %v", err)
+	anotherTask, err := fstaskparser.Read(anotherOutputDirectory)
+	require.NoErrorf(t, err, "failed to read task: %v", err)
+	assert.Equal(t, fstaskparser.EditorialReleaseImmediately, anotherTask.GetEditorialRelease())
+	editorials := anotherTask.GetAllEditorials()
+	require.Equal(t, 1, len(editorials))
+	assert.Equal(t, "lv", editorials[0].Language)
+	assert.Equal(t, md, *editorials[0].Markdown)
+}
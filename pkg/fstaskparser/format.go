@@ -0,0 +1,217 @@
+package fstaskparser
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// Format converts tasks from and to one task package format.
+type Format interface {
+	// Name identifies the format in the registry, e.g. "polygon"
+	Name() string
+	// Detect tells whether the package rooted at fsys is in this format
+	Detect(fsys fs.FS) bool
+	// Import reads a package, a directory or, where supported, a zip archive
+	Import(path string) (*Task, *ImportReport, error)
+	// Export writes the task to a path that must not exist yet
+	Export(t *Task, path string) (*ExportReport, error)
+}
+
+var (
+	ErrImportNotSupported = errors.New("import is not supported by this format")
+	ErrExportNotSupported = errors.New("export is not supported by this format")
+)
+
+var (
+	formatsMu sync.RWMutex
+	formats   []Format
+)
+
+// RegisterFormat adds a format to the registry. Formats are detected in the
+// order they are registered. It panics if the name is already taken, like
+// database/sql.Register.
+func RegisterFormat(f Format) {
+	formatsMu.Lock()
+	defer formatsMu.Unlock()
+
+	for _, existing := range formats {
+		if existing.Name() == f.Name() {
+			panic(fmt.Sprintf("format %s is already registered", f.Name()))
+		}
+	}
+	formats = append(formats, f)
+}
+
+// Formats returns the registered formats in detection order
+func Formats() []Format {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+
+	return append([]Format(nil), formats...)
+}
+
+// LookupFormat returns the registered format with the given name
+func LookupFormat(name string) (Format, error) {
+	for _, f := range Formats() {
+		if f.Name() == name {
+			return f, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown format %q", name)
+}
+
+// DetectFormat returns the first registered format that recognises the
+// package at path, either a directory or a zip archive.
+func DetectFormat(path string) (Format, error) {
+	fsys, closePkg, err := openPackage(path)
+	if err != nil {
+		return nil, err
+	}
+	defer closePkg()
+
+	for _, f := range Formats() {
+		if f.Detect(fsys) {
+			return f, nil
+		}
+	}
+	return nil, fmt.Errorf("format of %s not recognised", path)
+}
+
+// funcFormat implements Format with functions, nil imp or exp are not supported
+type funcFormat struct {
+	name   string
+	detect func(fsys fs.FS) bool
+	imp    func(path string) (*Task, *ImportReport, error)
+	exp    func(t *Task, path string) (*ExportReport, error)
+}
+
+func (f funcFormat) Name() string {
+	return f.name
+}
+
+func (f funcFormat) Detect(fsys fs.FS) bool {
+	return f.detect(fsys)
+}
+
+func (f funcFormat) Import(path string) (*Task, *ImportReport, error) {
+	if f.imp == nil {
+		return nil, nil, fmt.Errorf("%s: %w", f.name, ErrImportNotSupported)
+	}
+	return f.imp(path)
+}
+
+func (f funcFormat) Export(t *Task, path string) (*ExportReport, error) {
+	if f.exp == nil {
+		return nil, fmt.Errorf("%s: %w", f.name, ErrExportNotSupported)
+	}
+	return f.exp(t, path)
+}
+
+// the built-in formats, more specific layouts come first
+func init() {
+	RegisterFormat(funcFormat{
+		name:   "fstask",
+		detect: detectFSTask,
+		imp: func(path string) (*Task, *ImportReport, error) {
+			info, err := os.Stat(path)
+			if err != nil {
+				return nil, nil, fmt.Errorf("error opening task: %w", err)
+			}
+			if !info.IsDir() {
+				return nil, nil, fmt.Errorf("fstask tasks are read from directories: %s", path)
+			}
+			t, err := Read(path)
+			if err != nil {
+				return nil, nil, err
+			}
+			return t, &ImportReport{Format: "fstask"}, nil
+		},
+		exp: func(t *Task, path string) (*ExportReport, error) {
+			err := t.Store(path)
+			if err != nil {
+				return nil, err
+			}
+			return &ExportReport{Format: "fstask"}, nil
+		},
+	})
+	RegisterFormat(funcFormat{
+		name:   "polygon",
+		detect: func(fsys fs.FS) bool { return fileExistsFS(fsys, "problem.xml") },
+		imp:    ImportPolygon,
+	})
+	RegisterFormat(funcFormat{
+		name:   "cms",
+		detect: func(fsys fs.FS) bool { return fileExistsFS(fsys, "task.yaml") },
+		imp:    ImportCMS,
+		exp: func(t *Task, path string) (*ExportReport, error) {
+			return t.ExportCMS(path, CMSExportOptions{})
+		},
+	})
+	RegisterFormat(funcFormat{
+		name:   "domjudge",
+		detect: func(fsys fs.FS) bool { return fileExistsFS(fsys, "domjudge-problem.ini") },
+		imp:    ImportDOMjudge,
+		exp: func(t *Task, path string) (*ExportReport, error) {
+			return t.ExportDOMjudge(path, DOMjudgeExportOptions{})
+		},
+	})
+	RegisterFormat(funcFormat{
+		name:   "kattis",
+		detect: func(fsys fs.FS) bool { return fileExistsFS(fsys, "problem.yaml") },
+		imp:    ImportKattis,
+		exp:    (*Task).ExportKattis,
+	})
+	RegisterFormat(funcFormat{
+		name:   "tps",
+		detect: func(fsys fs.FS) bool { return fileExistsFS(fsys, "problem.json") },
+		imp:    ImportTPS,
+		exp: func(t *Task, path string) (*ExportReport, error) {
+			return t.ExportTPS(path, TPSExportOptions{})
+		},
+	})
+	RegisterFormat(funcFormat{
+		name:   "lio-legacy",
+		detect: detectLegacyLIO,
+		imp: func(path string) (*Task, *ImportReport, error) {
+			t, report, err := ImportLegacyLIO(path)
+			if err != nil {
+				return nil, nil, err
+			}
+			// guesses that need review are kept as unmapped parts
+			for _, g := range report.Guesses {
+				if g.Confidence != ConfidenceHigh {
+					report.Unmapped = append(report.Unmapped, fmt.Sprintf("%s confidence guess of %s: %s", g.Confidence, g.Subject, g.Detail))
+				}
+			}
+			return t, &report.ImportReport, nil
+		},
+	})
+}
+
+// detectFSTask recognises problem.toml with a specification version
+func detectFSTask(fsys fs.FS) bool {
+	content, err := fs.ReadFile(fsys, "problem.toml")
+	if err != nil {
+		return false
+	}
+	var keys map[string]any
+	if toml.Unmarshal(content, &keys) != nil {
+		return false
+	}
+	_, ok := keys["specification"]
+	return ok
+}
+
+// detectLegacyLIO recognises legacy test file names in a package without
+// problem.toml, the test files are not read
+func detectLegacyLIO(fsys fs.FS) bool {
+	if fileExistsFS(fsys, "problem.toml") {
+		return false
+	}
+	return hasLIOTestFiles(fsys)
+}
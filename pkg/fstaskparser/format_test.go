@@ -0,0 +1,96 @@
+package fstaskparser_test
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/programme-lv/fs-task-format-parser/pkg/fstaskparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectingFormats(t *testing.T) {
+	for path, name := range map[string]string{
+		testTaskPath:       "fstask",
+		polygonPackagePath: "polygon",
+		cmsTaskPath:        "cms",
+		kattisPackagePath:  "kattis",
+		tpsTaskPath:        "tps",
+		legacyLIOTaskPath:  "lio-legacy",
+	} {
+		f, err := fstaskparser.DetectFormat(path)
+		require.NoErrorf(t, err, "failed to detect format of %s: %v", path, err)
+		assert.Equal(t, name, f.Name(), path)
+	}
+
+	_, err := fstaskparser.DetectFormat(t.TempDir())
+	require.Error(t, err)
+
+	_, err = fstaskparser.LookupFormat("nonexistent")
+	require.Error(t, err)
+	assert.Panics(t, func() { fstaskparser.RegisterFormat(mustLookupFormat(t, "fstask")) })
+}
+
+func TestDetectingLegacyLIOFromFileNames(t *testing.T) {
+	var logged bytes.Buffer
+	log.SetOutput(&logged)
+	defer log.SetOutput(os.Stderr)
+
+	lio := mustLookupFormat(t, "lio-legacy")
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "uzd.i1"), nil, 0644))
+	_, err := fstaskparser.DetectFormat(dir)
+	require.Error(t, err, "an input without an answer is not a test")
+
+	// the empty files would log import warnings if they were read
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "uzd.o1"), nil, 0644))
+	f, err := fstaskparser.DetectFormat(dir)
+	require.NoErrorf(t, err, "failed to detect format: %v", err)
+	assert.Equal(t, lio.Name(), f.Name())
+	assert.Empty(t, logged.String())
+}
+
+func TestConvertingBetweenFormats(t *testing.T) {
+	tmpDirectory, err := os.MkdirTemp("", "fstaskparser-test-")
+	require.NoErrorf(t, err, "failed to create temporary directory: %v", err)
+	defer os.RemoveAll(tmpDirectory)
+
+	task, _, err := mustLookupFormat(t, "fstask").Import(testTaskPath)
+	require.NoErrorf(t, err, "failed to import task: %v", err)
+
+	// the DOMjudge zip is detected by domjudge-problem.ini, not as Kattis
+	zipPath := filepath.Join(tmpDirectory, "kvadrputekl.zip")
+	_, err = mustLookupFormat(t, "domjudge").Export(task, zipPath)
+	require.NoErrorf(t, err, "failed to export task: %v", err)
+	f, err := fstaskparser.DetectFormat(zipPath)
+	require.NoErrorf(t, err, "failed to detect format: %v", err)
+	require.Equal(t, "domjudge", f.Name())
+
+	imported, report, err := f.Import(zipPath)
+	require.NoErrorf(t, err, "failed to import exported task: %v", err)
+	assert.Equal(t, task.GetTaskName(), imported.GetTaskName())
+	assert.Equal(t, task.GetCPUTimeLimitInSeconds(), imported.GetCPUTimeLimitInSeconds())
+	assert.Equal(t, task.GetMemoryLimitInMegabytes(), imported.GetMemoryLimitInMegabytes())
+	assert.Equal(t, len(task.GetTestsSortedByID()), len(imported.GetTestsSortedByID()))
+	assert.Equal(t, []string{"domjudge-problem.ini key externalid"}, report.Unmapped)
+
+	fsTaskPath := filepath.Join(tmpDirectory, "fstask")
+	_, err = mustLookupFormat(t, "fstask").Export(imported, fsTaskPath)
+	require.NoErrorf(t, err, "failed to export task: %v", err)
+	f, err = fstaskparser.DetectFormat(fsTaskPath)
+	require.NoErrorf(t, err, "failed to detect format: %v", err)
+	assert.Equal(t, "fstask", f.Name())
+
+	_, err = mustLookupFormat(t, "polygon").Export(task, filepath.Join(tmpDirectory, "polygon"))
+	assert.True(t, errors.Is(err, fstaskparser.ErrExportNotSupported))
+}
+
+func mustLookupFormat(t *testing.T, name string) fstaskparser.Format {
+	f, err := fstaskparser.LookupFormat(name)
+	require.NoErrorf(t, err, "failed to look up format %s: %v", name, err)
+	return f
+}
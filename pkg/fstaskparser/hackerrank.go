@@ -0,0 +1,174 @@
+package fstaskparser
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// HackerRankGroup puts the tests with indices First..Last into one test group.
+type HackerRankGroup struct {
+	First   int
+	Last    int
+	Points  int
+	Public  bool
+	Subtask int // the position of the group, counting from 1, if 0
+}
+
+// HackerRankImportOptions supply what input00.txt / output00.txt bundles lack.
+type HackerRankImportOptions struct {
+	Name            string
+	CPUTimeSeconds  float64 // the NewTask default if 0
+	MemoryMegabytes int     // the NewTask default if 0
+	// Groups is the optional grouping rule, tests outside of all groups are left ungrouped
+	Groups []HackerRankGroup
+	// Examples are the indices of tests that are also used as examples
+	Examples []int
+}
+
+var hackerRankTestFileRegexp = regexp.MustCompile(`^(input|output)([0-9]+)\.txt$`)
+
+// ImportHackerRank reads a bundle of input/inputNN.txt and output/outputNN.txt
+// files, either a directory or a zip archive. Inputs and outputs are paired by
+// their numeric index, so input2.txt goes with output02.txt.
+func ImportHackerRank(packagePath string, opts HackerRankImportOptions) (*Task, *ImportReport, error) {
+	fsys, closePkg, err := openPackage(packagePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closePkg()
+
+	return ImportHackerRankFS(fsys, opts)
+}
+
+// ImportHackerRankFS reads a bundle of inputNN.txt and outputNN.txt files rooted at fsys.
+func ImportHackerRankFS(fsys fs.FS, opts HackerRankImportOptions) (*Task, *ImportReport, error) {
+	report := &ImportReport{Format: "hackerrank"}
+	if opts.Name == "" {
+		return nil, nil, fmt.Errorf("task name is required")
+	}
+
+	t, err := NewTask(opts.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if opts.CPUTimeSeconds > 0 {
+		t.cpuTimeSeconds = opts.CPUTimeSeconds
+	}
+	if opts.MemoryMegabytes > 0 {
+		t.memoryMegabytes = opts.MemoryMegabytes
+	}
+
+	// index -> [input path, output path]
+	files := make(map[int]*[2]string)
+	err = fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		m := hackerRankTestFileRegexp.FindStringSubmatch(path.Base(p))
+		if m == nil {
+			report.unmapped("file %s", p)
+			return nil
+		}
+		index, err := strconv.Atoi(m[2])
+		if err != nil {
+			return fmt.Errorf("invalid test index in %s: %w", p, err)
+		}
+		if files[index] == nil {
+			files[index] = &[2]string{}
+		}
+		slot := 0
+		if m[1] == "output" {
+			slot = 1
+		}
+		if files[index][slot] != "" {
+			return fmt.Errorf("test %d has both %s and %s", index, files[index][slot], p)
+		}
+		files[index][slot] = p
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	indices := make([]int, 0, len(files))
+	for index := range files {
+		indices = append(indices, index)
+	}
+	sort.Ints(indices)
+
+	testIDs := make(map[int]int, len(indices))
+	// names are zero padded to the widest index, at least 2 digits
+	width := 2
+	if len(indices) > 0 {
+		width = max(width, len(strconv.Itoa(indices[len(indices)-1])))
+	}
+	for _, index := range indices {
+		pair := files[index]
+		if pair[0] == "" || pair[1] == "" {
+			report.unmapped("test %d without input or output", index)
+			continue
+		}
+		input, err := fs.ReadFile(fsys, pair[0])
+		if err != nil {
+			return nil, nil, fmt.Errorf("error reading test input: %w", err)
+		}
+		output, err := fs.ReadFile(fsys, pair[1])
+		if err != nil {
+			return nil, nil, fmt.Errorf("error reading test output: %w", err)
+		}
+		id := t.AddTest(input, output)
+		t.AssignFilenameToTest(fmt.Sprintf("%0*d", width, index), id)
+		testIDs[index] = id
+	}
+	if len(testIDs) == 0 {
+		return nil, nil, fmt.Errorf("no inputNN.txt and outputNN.txt pairs found")
+	}
+
+	grouped := make(map[int]bool)
+	for i, g := range opts.Groups {
+		if g.First > g.Last {
+			return nil, nil, fmt.Errorf("group %d: first index %d is after last %d", i+1, g.First, g.Last)
+		}
+		ids := make([]int, 0, g.Last-g.First+1)
+		for index := g.First; index <= g.Last; index++ {
+			id, ok := testIDs[index]
+			if !ok {
+				return nil, nil, fmt.Errorf("group %d: test %d not found", i+1, index)
+			}
+			if grouped[index] {
+				return nil, nil, fmt.Errorf("group %d: test %d is already in a group", i+1, index)
+			}
+			grouped[index] = true
+			ids = append(ids, id)
+		}
+		subtask := g.Subtask
+		if subtask == 0 {
+			subtask = i + 1
+		}
+		err = t.AddTestGroupWithID(i+1, g.Points, g.Public, ids, subtask)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if len(opts.Groups) > 0 && len(grouped) < len(testIDs) {
+		report.unmapped("%d tests are outside of the groups", len(testIDs)-len(grouped))
+	}
+
+	for _, index := range opts.Examples {
+		id, ok := testIDs[index]
+		if !ok {
+			return nil, nil, fmt.Errorf("example test %d not found", index)
+		}
+		for _, test := range t.tests {
+			if test.ID == id {
+				t.AddExample(test.Input, test.Answer, nil)
+			}
+		}
+	}
+
+	return t, report, nil
+}
@@ -0,0 +1,102 @@
+package fstaskparser_test
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/programme-lv/fs-task-format-parser/pkg/fstaskparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportingHackerRankBundle(t *testing.T) {
+	// pairs are matched by index: input2.txt goes with output02.txt,
+	// even though they sort differently
+	fsys := fstest.MapFS{
+		"input/input00.txt":   {Data: []byte("1 2\n")},
+		"output/output00.txt": {Data: []byte("3\n")},
+		"input/input1.txt":    {Data: []byte("5 7\n")},
+		"output/output01.txt": {Data: []byte("12\n")},
+		"input/input2.txt":    {Data: []byte("0 0\n")},
+		"output/output02.txt": {Data: []byte("0\n")},
+		"input/input10.txt":   {Data: []byte("1000000000 1000000000\n")},
+		"output/output10.txt": {Data: []byte("2000000000\n")},
+		"input/input11.txt":   {Data: []byte("1 1\n")},
+		"README.md":           {Data: []byte("A plus B\n")},
+	}
+
+	task, report, err := fstaskparser.ImportHackerRankFS(fsys, fstaskparser.HackerRankImportOptions{
+		Name:            "A plus B",
+		CPUTimeSeconds:  0.5,
+		MemoryMegabytes: 64,
+		Groups: []fstaskparser.HackerRankGroup{
+			{First: 0, Last: 0, Points: 0, Public: true},
+			{First: 1, Last: 2, Points: 40},
+			{First: 10, Last: 10, Points: 60, Subtask: 5},
+		},
+		Examples: []int{0},
+	})
+	require.NoErrorf(t, err, "failed to import bundle: %v", err)
+
+	assert.Equal(t, "A plus B", task.GetTaskName())
+	assert.Equal(t, 0.5, task.GetCPUTimeLimitInSeconds())
+	assert.Equal(t, 64, task.GetMemoryLimitInMegabytes())
+
+	tests := task.GetTestsSortedByID()
+	require.Equal(t, 4, len(tests))
+	assert.Equal(t, []byte("0 0\n"), tests[2].Input)
+	assert.Equal(t, []byte("0\n"), tests[2].Answer)
+	assert.Equal(t, []byte("2000000000\n"), tests[3].Answer)
+	assert.Equal(t, "02", task.GetTestFilenameFromID(3))
+	assert.Equal(t, "10", task.GetTestFilenameFromID(4))
+
+	require.Equal(t, []int{1, 2, 3}, task.GetTestGroupIDs())
+	assert.Equal(t, fstaskparser.TestGroupInfo{GroupID: 1, Points: 0, Public: true, TestIDs: []int{1}, Subtask: 1}, task.GetInfoOnTestGroup(1))
+	assert.Equal(t, fstaskparser.TestGroupInfo{GroupID: 2, Points: 40, Public: false, TestIDs: []int{2, 3}, Subtask: 2}, task.GetInfoOnTestGroup(2))
+	assert.Equal(t, fstaskparser.TestGroupInfo{GroupID: 3, Points: 60, Public: false, TestIDs: []int{4}, Subtask: 5}, task.GetInfoOnTestGroup(3))
+
+	require.Equal(t, 1, len(task.GetExamples()))
+	assert.Equal(t, []byte("3\n"), task.GetExamples()[0].Output)
+
+	assert.Equal(t, []string{"file README.md", "test 11 without input or output"}, report.Unmapped)
+
+	_, _, err = fstaskparser.ImportHackerRankFS(fsys, fstaskparser.HackerRankImportOptions{
+		Name:   "A plus B",
+		Groups: []fstaskparser.HackerRankGroup{{First: 1, Last: 3, Points: 100}},
+	})
+	require.Error(t, err)
+}
+
+func TestImportingZippedHackerRankBundle(t *testing.T) {
+	tmpDirectory, err := os.MkdirTemp("", "fstaskparser-test-")
+	require.NoErrorf(t, err, "failed to create temporary directory: %v", err)
+	defer os.RemoveAll(tmpDirectory)
+
+	zipPath := filepath.Join(tmpDirectory, "bundle.zip")
+	f, err := os.Create(zipPath)
+	require.NoErrorf(t, err, "failed to create zip: %v", err)
+	zw := zip.NewWriter(f)
+	for name, content := range map[string]string{
+		"input/input00.txt":   "1 2\n",
+		"output/output00.txt": "3\n",
+		"input/input01.txt":   "5 7\n",
+		"output/output01.txt": "12\n",
+	} {
+		w, err := zw.Create(name)
+		require.NoErrorf(t, err, "failed to add %s: %v", name, err)
+		_, err = w.Write([]byte(content))
+		require.NoErrorf(t, err, "failed to write %s: %v", name, err)
+	}
+	require.NoError(t, zw.Close())
+	require.NoError(t, f.Close())
+
+	task, report, err := fstaskparser.ImportHackerRank(zipPath, fstaskparser.HackerRankImportOptions{Name: "A plus B"})
+	require.NoErrorf(t, err, "failed to import bundle: %v", err)
+	assert.Equal(t, 2, len(task.GetTestsSortedByID()))
+	assert.Empty(t, task.GetTestGroupIDs())
+	assert.Equal(t, 1.0, task.GetCPUTimeLimitInSeconds())
+	assert.Empty(t, report.Unmapped)
+}
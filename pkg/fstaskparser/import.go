@@ -0,0 +1,65 @@
+package fstaskparser
+
+import (
+	"archive/zip"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"strings"
+)
+
+// ImportReport lists what an importer could not carry over into the Task.
+type ImportReport struct {
+	Format   string   // source format, e.g. "polygon"
+	Unmapped []string // human readable descriptions of skipped or approximated parts
+}
+
+func (r *ImportReport) unmapped(format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	log.Printf("Warning: %s import: %s\n", r.Format, msg)
+	r.Unmapped = append(r.Unmapped, msg)
+}
+
+func (r *ImportReport) String() string {
+	if len(r.Unmapped) == 0 {
+		return fmt.Sprintf("%s import: everything mapped", r.Format)
+	}
+	return fmt.Sprintf("%s import:\n- %s", r.Format, strings.Join(r.Unmapped, "\n- "))
+}
+
+// ExportReport lists what an exporter could not express in the target format.
+type ExportReport struct {
+	Format  string   // target format, e.g. "cms"
+	Dropped []string // human readable descriptions of left out or approximated parts
+}
+
+func (r *ExportReport) dropped(format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	log.Printf("Warning: %s export: %s\n", r.Format, msg)
+	r.Dropped = append(r.Dropped, msg)
+}
+
+func (r *ExportReport) String() string {
+	if len(r.Dropped) == 0 {
+		return fmt.Sprintf("%s export: everything written", r.Format)
+	}
+	return fmt.Sprintf("%s export:\n- %s", r.Format, strings.Join(r.Dropped, "\n- "))
+}
+
+// openPackage opens a task package given either as a directory or as a zip archive.
+func openPackage(path string) (fs.FS, func() error, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error opening package: %w", err)
+	}
+	if info.IsDir() {
+		return os.DirFS(path), func() error { return nil }, nil
+	}
+
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error opening package archive: %w", err)
+	}
+	return zr, zr.Close, nil
+}
@@ -0,0 +1,177 @@
+package fstaskparser
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// jsonSchema is the subset of JSON Schema draft-07 used to describe problem.toml.
+// It is understood by Taplo and the Even Better TOML VS Code extension.
+type jsonSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Properties           map[string]*jsonSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties any                    `json:"additionalProperties,omitempty"`
+	Items                *jsonSchema            `json:"items,omitempty"`
+	Minimum              *float64               `json:"minimum,omitempty"`
+	ExclusiveMinimum     *float64               `json:"exclusiveMinimum,omitempty"`
+	Maximum              *float64               `json:"maximum,omitempty"`
+}
+
+type pTomlKeyDoc struct {
+	Description      string
+	Required         bool
+	Minimum          *float64
+	ExclusiveMinimum *float64
+	Maximum          *float64
+}
+
+func float64Ptr(f float64) *float64 {
+	return &f
+}
+
+// pTomlKeyDocs documents problem.toml keys by their dotted path.
+// Every key of every schema version must be documented here.
+var pTomlKeyDocs = map[string]pTomlKeyDoc{
+	"specification":          {Description: "Version of the task format specification, e.g. \"v2.4.0\".", Required: true},
+	"task_name":              {Description: "Full name of the task as shown to contestants.", Required: true},
+	"metadata":               {Description: "Information about the task that does not affect evaluation."},
+	"constraints":            {Description: "Evaluation limits."},
+	"test_groups":            {Description: "Test groups as used in Latvia's informatics olympiad. Each group belongs to a subtask."},
+	"illustration_image":     {Description: "Path of the task illustration image relative to the assets directory."},
+	"visible_input_subtasks": {Description: "Subtasks whose test inputs are shown to contestants."},
+	"test_id_overwrite":      {Description: "Test IDs overriding the lexicographic order of test filenames."},
+	"editorial":              {Description: "Solution write-ups stored in the editorial directory."},
+	"statement_sections":     {Description: "Visibility, \"contestants\" or \"staff\", of additional statement sections by name."},
+
+	"metadata.problem_tags":       {Description: "Topics the task covers."},
+	"metadata.difficulty_1_to_5":  {Description: "Difficulty of the task from 1 (easiest) to 5 (hardest).", Minimum: float64Ptr(1), Maximum: float64Ptr(5)},
+	"metadata.task_authors":       {Description: "Authors of the task."},
+	"metadata.origin_olympiad":    {Description: "Olympiad the task originates from, e.g. \"LIO\"."},
+	"metadata.origin_notes":       {Description: "Notes on the origin of the task by language."},
+	"metadata.origin_institution": {Description: "Institution that created the task."},
+
+	"editorial.release": {Description: "When editorials may be published: \"after_contest\" or \"immediately\".", Required: true},
+
+	"constraints.memory_megabytes": {Description: "Memory limit in megabytes.", Minimum: float64Ptr(1)},
+	"constraints.cpu_time_seconds": {Description: "CPU time limit in seconds.", ExclusiveMinimum: float64Ptr(0)},
+
+	"test_groups.group_id":       {Description: "Unique identifier of the test group.", Required: true},
+	"test_groups.points":         {Description: "Points awarded for passing every test in the group.", Minimum: float64Ptr(0)},
+	"test_groups.public":         {Description: "Whether the group results are shown to contestants during the contest."},
+	"test_groups.subtask":        {Description: "Subtask the group belongs to."},
+	"test_groups.test_ids":       {Description: "IDs of the tests in the group."},
+	"test_groups.test_filenames": {Description: "Filenames, without extension, of the tests in the group."},
+}
+
+// SupportedSpecVersions lists the specification versions, oldest first,
+// at which the problem.toml layout changed.
+func SupportedSpecVersions() []SpecVersion {
+	res := make([]SpecVersion, 0, len(problemTomlSchemas))
+	for _, s := range problemTomlSchemas {
+		res = append(res, s.since)
+	}
+	return res
+}
+
+// ProblemTomlJSONSchema returns the JSON Schema describing problem.toml
+// of the given specification version.
+func ProblemTomlJSONSchema(specVers SpecVersion) ([]byte, error) {
+	schema, err := getProblemTomlSchema(specVers)
+	if err != nil {
+		return nil, err
+	}
+
+	typ := reflect.TypeOf(schema.newTarget()).Elem()
+	root, err := structJSONSchema(typ, "")
+	if err != nil {
+		return nil, fmt.Errorf("error generating schema: %w", err)
+	}
+	root.Schema = "http://json-schema.org/draft-07/schema#"
+	root.Title = fmt.Sprintf("problem.toml (specification %s)", schema.since)
+	root.Description = "Task description file of the programme.lv file system task format."
+
+	res, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling schema: %w", err)
+	}
+	return append(res, '\n'), nil
+}
+
+func structJSONSchema(typ reflect.Type, prefix string) (*jsonSchema, error) {
+	res := &jsonSchema{
+		Type:                 "object",
+		Properties:           map[string]*jsonSchema{},
+		AdditionalProperties: false,
+	}
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		name := strings.Split(field.Tag.Get("toml"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+
+		doc, ok := pTomlKeyDocs[path]
+		if !ok {
+			return nil, fmt.Errorf("undocumented problem.toml key: %s", path)
+		}
+
+		prop, err := typeJSONSchema(field.Type, path)
+		if err != nil {
+			return nil, err
+		}
+		prop.Description = doc.Description
+		prop.Minimum = doc.Minimum
+		prop.ExclusiveMinimum = doc.ExclusiveMinimum
+		prop.Maximum = doc.Maximum
+
+		res.Properties[name] = prop
+		if doc.Required {
+			res.Required = append(res.Required, name)
+		}
+	}
+
+	return res, nil
+}
+
+func typeJSONSchema(typ reflect.Type, path string) (*jsonSchema, error) {
+	switch typ.Kind() {
+	case reflect.String:
+		return &jsonSchema{Type: "string"}, nil
+	case reflect.Bool:
+		return &jsonSchema{Type: "boolean"}, nil
+	case reflect.Int, reflect.Int64, reflect.Int32:
+		return &jsonSchema{Type: "integer"}, nil
+	case reflect.Float64, reflect.Float32:
+		return &jsonSchema{Type: "number"}, nil
+	case reflect.Slice:
+		items, err := typeJSONSchema(typ.Elem(), path)
+		if err != nil {
+			return nil, err
+		}
+		return &jsonSchema{Type: "array", Items: items}, nil
+	case reflect.Map:
+		values, err := typeJSONSchema(typ.Elem(), path)
+		if err != nil {
+			return nil, err
+		}
+		return &jsonSchema{Type: "object", AdditionalProperties: values}, nil
+	case reflect.Struct:
+		return structJSONSchema(typ, path)
+	case reflect.Pointer:
+		return typeJSONSchema(typ.Elem(), path)
+	default:
+		return nil, fmt.Errorf("unsupported type %s of key %s", typ, path)
+	}
+}
@@ -0,0 +1,74 @@
+package fstaskparser_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/programme-lv/fs-task-format-parser/pkg/fstaskparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// the published schemas must match the ones generated from the Go types,
+// regenerate them with `go run ./cmd/fstask schema -o schemas`
+func TestPublishedJSONSchemasAreInSync(t *testing.T) {
+	for _, v := range fstaskparser.SupportedSpecVersions() {
+		generated, err := fstaskparser.ProblemTomlJSONSchema(v)
+		require.NoErrorf(t, err, "failed to generate schema: %v", err)
+
+		path := filepath.Join(prjRootPath, "schemas", fmt.Sprintf("problem-toml-%s.schema.json", v))
+		published, err := os.ReadFile(path)
+		require.NoErrorf(t, err, "failed to read published schema: %v", err)
+
+		assert.Equalf(t, string(published), string(generated), "schema %s is out of date", path)
+	}
+}
+
+func TestJSONSchemaCoversTestTask(t *testing.T) {
+	pTomlContent, err := os.ReadFile(filepath.Join(testTaskPath, "problem.toml"))
+	require.NoErrorf(t, err, "failed to read problem.toml: %v", err)
+
+	var pToml map[string]any
+	err = toml.Unmarshal(pTomlContent, &pToml)
+	require.NoErrorf(t, err, "failed to unmarshal problem.toml: %v", err)
+
+	schemaContent, err := fstaskparser.ProblemTomlJSONSchema(fstaskparser.MustParseSpecVersion(pToml["specification"].(string)))
+	require.NoErrorf(t, err, "failed to generate schema: %v", err)
+
+	var schema map[string]any
+	err = json.Unmarshal(schemaContent, &schema)
+	require.NoErrorf(t, err, "failed to unmarshal schema: %v", err)
+
+	assertKeysInSchema(t, pToml, schema, "")
+}
+
+func assertKeysInSchema(t *testing.T, value any, schema map[string]any, path string) {
+	switch v := value.(type) {
+	case map[string]any:
+		props, ok := schema["properties"].(map[string]any)
+		if !ok {
+			// free-form table such as origin notes
+			require.Containsf(t, schema, "additionalProperties", "no properties for %s", path)
+			return
+		}
+		for key, child := range v {
+			childSchema, ok := props[key].(map[string]any)
+			require.Truef(t, ok, "key %s.%s is missing in schema", path, key)
+			assertKeysInSchema(t, child, childSchema, path+"."+key)
+		}
+		required, _ := schema["required"].([]any)
+		for _, req := range required {
+			assert.Containsf(t, v, req, "required key %s.%s is missing", path, req)
+		}
+	case []any:
+		items, ok := schema["items"].(map[string]any)
+		require.Truef(t, ok, "no items for %s", path)
+		for _, child := range v {
+			assertKeysInSchema(t, child, items, path)
+		}
+	}
+}
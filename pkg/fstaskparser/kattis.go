@@ -0,0 +1,720 @@
+package fstaskparser
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// kattisProblemYAML is problem.yaml of the Kattis problem package format
+type kattisProblemYAML struct {
+	Name       any               `yaml:"name,omitempty"` // string or map of language to name
+	Type       string            `yaml:"type,omitempty"`
+	Author     string            `yaml:"author,omitempty"`
+	Source     string            `yaml:"source,omitempty"`
+	Keywords   string            `yaml:"keywords,omitempty"`
+	Validation string            `yaml:"validation,omitempty"`
+	Limits     *kattisLimitsYAML `yaml:"limits,omitempty"`
+}
+
+type kattisLimitsYAML struct {
+	TimeLimit float64 `yaml:"time_limit,omitempty"`
+	Memory    int     `yaml:"memory,omitempty"`
+}
+
+// kattisTestdataYAML is testdata.yaml of a Kattis test data group
+type kattisTestdataYAML struct {
+	OnReject    string   `yaml:"on_reject,omitempty"`
+	GraderFlags string   `yaml:"grader_flags,omitempty"`
+	AcceptScore *float64 `yaml:"accept_score,omitempty"`
+	Range       string   `yaml:"range,omitempty"`
+}
+
+// kattisMappedKeys are the problem.yaml keys the importer reads
+var kattisMappedKeys = map[string]bool{
+	"name":                   true,
+	"type":                   true,
+	"author":                 true,
+	"source":                 true,
+	"keywords":               true,
+	"validation":             true,
+	"limits":                 true,
+	"problem_format_version": true,
+	"uuid":                   true,
+}
+
+// kattisUnmappedDirs are package directories without a counterpart in the task
+var kattisUnmappedDirs = []string{
+	"input_validators", "input_format_validators", "submissions",
+	"generators", "include", "attachments", "graders",
+}
+
+// statementSectionHeadings map problem statement headings to Markdown statement parts
+var statementSectionHeadings = map[string]string{
+	"input":   "input",
+	"output":  "output",
+	"scoring": "scoring",
+	"notes":   "notes",
+	"note":    "notes",
+}
+
+var kattisGroupDirRegexp = regexp.MustCompile(`^group([0-9]+)-subtask([0-9]+)$`)
+
+var kattisStatementFileRegexp = regexp.MustCompile(`^problem(\.([A-Za-z-]+))?\.(md|tex)$`)
+
+// kattisUngroupedDir holds the 0 point tests of a scoring problem outside of groups
+const kattisUngroupedDir = "ungrouped"
+
+// ExportKattis writes the task as a problemtools compatible Kattis problem
+// package. Test groups become data/secret/group<ID>-subtask<N>/ directories
+// whose testdata.yaml gives every test the group points and takes the
+// minimum, so a group scores only when all its tests pass.
+func (t *Task) ExportKattis(dirPath string) (*ExportReport, error) {
+	report := &ExportReport{Format: "kattis"}
+	if _, err := os.Stat(dirPath); !os.IsNotExist(err) {
+		return nil, fmt.Errorf("directory already exists: %s", dirPath)
+	}
+
+	files := make(map[string][]byte)
+	py := kattisProblemYAML{
+		Name:     t.taskName,
+		Author:   strings.Join(t.problemAuthors, ", "),
+		Source:   t.originOlympiad,
+		Keywords: strings.Join(t.problemTags, " "),
+		Limits:   &kattisLimitsYAML{TimeLimit: t.cpuTimeSeconds, Memory: t.memoryMegabytes},
+	}
+	// problemtools derives the time limit from the judge solutions, DOMjudge reads .timelimit
+	files[".timelimit"] = []byte(strconv.FormatFloat(t.cpuTimeSeconds, 'f', -1, 64) + "\n")
+
+	for i, e := range t.examples {
+		name := fmt.Sprintf("%03d", i+1)
+		if e.Name != nil {
+			name = *e.Name
+		}
+		files["data/sample/"+name+".in"] = e.Input
+		files["data/sample/"+name+".ans"] = e.Output
+		if len(e.MdNote) > 0 {
+			report.dropped("note of example %s", name)
+		}
+	}
+
+	testsByID := make(map[int]test, len(t.tests))
+	for _, test := range t.tests {
+		testsByID[test.ID] = test
+	}
+	writeTest := func(dir string, id int) {
+		name := strings.ReplaceAll(t.getTestToBeWrittenFname(id), "/", "_")
+		files[path.Join(dir, name+".in")] = testsByID[id].Input
+		files[path.Join(dir, name+".ans")] = testsByID[id].Answer
+	}
+
+	if len(t.testGroupIDs) == 0 {
+		for _, test := range t.GetTestsSortedByID() {
+			writeTest("data/secret", test.ID)
+		}
+	} else {
+		py.Type = "scoring"
+		total := 0
+		grouped := make(map[int]bool)
+		for _, groupID := range t.testGroupIDs {
+			points := t.tGroupPoints[groupID]
+			total += points
+			dir := fmt.Sprintf("data/secret/group%02d-subtask%02d", groupID, t.tGroupToStMap[groupID])
+			testdata, err := encodeYAML(kattisGroupTestdata(points))
+			if err != nil {
+				return nil, err
+			}
+			files[dir+"/testdata.yaml"] = testdata
+			for _, id := range t.tGroupTestIDs[groupID] {
+				writeTest(dir, id)
+				grouped[id] = true
+			}
+			if t.isTGroupPublic[groupID] {
+				report.dropped("test group %d is public, Kattis shows only data/sample", groupID)
+			}
+		}
+
+		ungrouped := 0
+		for _, test := range t.GetTestsSortedByID() {
+			if !grouped[test.ID] {
+				writeTest("data/secret/"+kattisUngroupedDir, test.ID)
+				ungrouped++
+			}
+		}
+		if ungrouped > 0 {
+			report.dropped("%d tests outside of groups exported as a 0 point group", ungrouped)
+			testdata, err := encodeYAML(kattisGroupTestdata(0))
+			if err != nil {
+				return nil, err
+			}
+			files["data/secret/"+kattisUngroupedDir+"/testdata.yaml"] = testdata
+		}
+
+		for name, td := range map[string]kattisTestdataYAML{
+			"data/testdata.yaml":        {OnReject: "continue", GraderFlags: "ignore_sample", Range: fmt.Sprintf("0 %d", total)},
+			"data/secret/testdata.yaml": {OnReject: "continue", GraderFlags: "sum", Range: fmt.Sprintf("0 %d", total)},
+		} {
+			testdata, err := encodeYAML(td)
+			if err != nil {
+				return nil, err
+			}
+			files[name] = testdata
+		}
+	}
+	if len(t.visibleInputSubtasks) > 0 {
+		report.dropped("visible input subtasks %v", t.visibleInputSubtasks)
+	}
+
+	if t.checker != nil {
+		py.Validation = "custom"
+		files["output_validators/checker/checker.cpp"] = t.checker
+		report.dropped("checker exported as output validator, it must follow the Kattis output validator interface")
+	}
+
+	for _, st := range t.GetResolvedMarkdownStatements() {
+		files["problem_statement/problem."+*st.Language+".md"] = []byte(t.headedStatementMarkdown(st, report))
+	}
+	for _, a := range t.assets {
+		files["problem_statement/"+a.RelativePath] = a.Content
+	}
+	for _, pdf := range t.GetAllPDFStatements() {
+		report.dropped("pdf statement in %s", pdf.Language)
+	}
+	for _, e := range t.GetAllEditorials() {
+		report.dropped("editorial in %s", e.Language)
+	}
+
+	content, err := encodeYAML(py)
+	if err != nil {
+		return nil, err
+	}
+	files["problem.yaml"] = content
+
+	err = writeFiles(dirPath, files)
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func kattisGroupTestdata(points int) kattisTestdataYAML {
+	score := float64(points)
+	return kattisTestdataYAML{
+		OnReject:    "break",
+		GraderFlags: "min",
+		AcceptScore: &score,
+		Range:       fmt.Sprintf("0 %d", points),
+	}
+}
+
+// headedStatementMarkdown joins the statement parts under "## <Section>"
+// headings, the layout problemtools expects of problem.<lang>.md and TPS of
+// statement/<lang>.md
+func (t *Task) headedStatementMarkdown(st MarkdownStatement, report *ExportReport) string {
+	var b strings.Builder
+	b.WriteString(strings.TrimSpace(st.Story) + "\n")
+	writeSection := func(heading string, md string) {
+		fmt.Fprintf(&b, "\n## %s\n\n%s\n", heading, strings.TrimSpace(md))
+	}
+	writeSection("Input", st.Input)
+	writeSection("Output", st.Output)
+	for _, name := range sortedSectionNames(st.Sections) {
+		if t.GetStatementSectionVisibility(name) != SectionVisibleToContestants {
+			report.dropped("staff section %s of the statement in %s", name, *st.Language)
+			continue
+		}
+		writeSection(sectionTitle(name), st.Sections[name])
+	}
+	if st.Scoring != nil {
+		writeSection("Scoring", *st.Scoring)
+	}
+	if st.Notes != nil {
+		writeSection("Notes", *st.Notes)
+	}
+	return b.String()
+}
+
+// sectionTitle turns a section name such as "input_format" into "Input format"
+func sectionTitle(name string) string {
+	title := strings.ReplaceAll(name, "_", " ")
+	return strings.ToUpper(title[:1]) + title[1:]
+}
+
+func encodeYAML(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	err := encoder.Encode(v)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding yaml: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// ImportKattis reads a Kattis / ICPC problem package, either a directory or
+// a zip archive. Subdirectories of data/secret become test groups.
+func ImportKattis(packagePath string) (*Task, *ImportReport, error) {
+	fsys, closePkg, err := openPackage(packagePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closePkg()
+
+	return ImportKattisFS(fsys)
+}
+
+// ImportKattisFS reads a Kattis / ICPC problem package rooted at fsys.
+func ImportKattisFS(fsys fs.FS) (*Task, *ImportReport, error) {
+	report := &ImportReport{Format: "kattis"}
+
+	content, err := fs.ReadFile(fsys, "problem.yaml")
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading problem.yaml: %w", err)
+	}
+	var py kattisProblemYAML
+	err = yaml.Unmarshal(content, &py)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error parsing problem.yaml: %w", err)
+	}
+	var keys map[string]any
+	err = yaml.Unmarshal(content, &keys)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error parsing problem.yaml: %w", err)
+	}
+	for _, key := range sortedKeys(keys) {
+		if !kattisMappedKeys[key] {
+			report.unmapped("problem.yaml key %s", key)
+		}
+	}
+	if limits, ok := keys["limits"].(map[string]any); ok {
+		for _, key := range sortedKeys(limits) {
+			if key != "time_limit" && key != "memory" {
+				report.unmapped("problem.yaml limit %s", key)
+			}
+		}
+	}
+
+	name, err := kattisProblemName(py.Name, report)
+	if err != nil {
+		return nil, nil, err
+	}
+	t, err := NewTask(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if py.Author != "" {
+		t.problemAuthors = strings.Split(py.Author, ", ")
+	}
+	t.originOlympiad = py.Source
+	if py.Keywords != "" {
+		t.problemTags = strings.Fields(py.Keywords)
+	}
+	if py.Limits != nil {
+		if py.Limits.Memory > 0 {
+			t.memoryMegabytes = py.Limits.Memory
+		}
+		t.cpuTimeSeconds = py.Limits.TimeLimit
+	}
+	if tl, err := fs.ReadFile(fsys, ".timelimit"); err == nil {
+		t.cpuTimeSeconds, err = strconv.ParseFloat(strings.TrimSpace(string(tl)), 64)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid .timelimit: %w", err)
+		}
+	}
+	if t.cpuTimeSeconds == 0 {
+		report.unmapped("no time limit, problemtools derives it from the judge solutions")
+	}
+
+	samples, err := readKattisTestDir(fsys, "data/sample", report)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, s := range samples {
+		name := s.name
+		t.examples = append(t.examples, example{Input: s.input, Output: s.answer, Name: &name})
+	}
+
+	err = t.importKattisSecret(fsys, py.Type == "scoring", report)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if strings.HasPrefix(py.Validation, "custom") {
+		t.checker = readKattisValidator(fsys, report)
+	}
+
+	err = t.importKattisStatements(fsys, report)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, dir := range kattisUnmappedDirs {
+		if entries, err := fs.ReadDir(fsys, dir); err == nil && len(entries) > 0 {
+			report.unmapped("directory %s", dir)
+		}
+	}
+
+	return t, report, nil
+}
+
+func kattisProblemName(name any, report *ImportReport) (string, error) {
+	switch n := name.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return n, nil
+	case map[string]any:
+		if len(n) == 0 {
+			return "", nil
+		}
+		langs := sortedKeys(n)
+		chosen := langs[0]
+		if _, ok := n["en"]; ok {
+			chosen = "en"
+		}
+		for _, lang := range langs {
+			if lang != chosen {
+				report.unmapped("problem name %q in %s", n[lang], lang)
+			}
+		}
+		return fmt.Sprint(n[chosen]), nil
+	}
+	return "", fmt.Errorf("invalid problem name %v", name)
+}
+
+// namedTest is a test read from <name>.in and its answer file
+type namedTest struct {
+	name   string
+	input  []byte
+	answer []byte
+}
+
+// readKattisTestDir reads the <name>.in and <name>.ans pairs of a directory
+// sorted by name, a missing directory has no tests.
+func readKattisTestDir(fsys fs.FS, dirPath string, report *ImportReport) ([]namedTest, error) {
+	entries, err := fs.ReadDir(fsys, dirPath)
+	if err != nil {
+		return nil, nil
+	}
+
+	res := make([]namedTest, 0)
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".in") {
+			continue
+		}
+		name := strings.TrimSuffix(e.Name(), ".in")
+		input, err := fs.ReadFile(fsys, path.Join(dirPath, e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("error reading test input: %w", err)
+		}
+		answer, err := fs.ReadFile(fsys, path.Join(dirPath, name+".ans"))
+		if err != nil {
+			report.unmapped("test %s/%s without answer", dirPath, name)
+			continue
+		}
+		res = append(res, namedTest{name: name, input: input, answer: answer})
+	}
+	for _, e := range entries {
+		ext := path.Ext(e.Name())
+		if !e.IsDir() && ext != ".in" && ext != ".ans" && e.Name() != "testdata.yaml" {
+			report.unmapped("file %s/%s", dirPath, e.Name())
+		}
+	}
+	return res, nil
+}
+
+func readKattisTestdata(fsys fs.FS, dirPath string, parent kattisTestdataYAML) (kattisTestdataYAML, error) {
+	content, err := fs.ReadFile(fsys, path.Join(dirPath, "testdata.yaml"))
+	if err != nil {
+		return parent, nil
+	}
+	res := parent
+	err = yaml.Unmarshal(content, &res)
+	if err != nil {
+		return parent, fmt.Errorf("error parsing %s/testdata.yaml: %w", dirPath, err)
+	}
+	return res, nil
+}
+
+func (t *Task) importKattisSecret(fsys fs.FS, scoring bool, report *ImportReport) error {
+	secret, err := readKattisTestdata(fsys, "data/secret", kattisTestdataYAML{})
+	if err != nil {
+		return err
+	}
+
+	addTests := func(tests []namedTest) []int {
+		ids := make([]int, 0, len(tests))
+		for _, kt := range tests {
+			id := t.AddTest(kt.input, kt.answer)
+			if _, taken := t.testFilenameToID[kt.name]; !taken {
+				t.AssignFilenameToTest(kt.name, id)
+			}
+			ids = append(ids, id)
+		}
+		return ids
+	}
+
+	tests, err := readKattisTestDir(fsys, "data/secret", report)
+	if err != nil {
+		return err
+	}
+	addTests(tests)
+
+	entries, err := fs.ReadDir(fsys, "data/secret")
+	if err != nil {
+		return nil
+	}
+	groupDirs := make([]string, 0)
+	for _, e := range entries {
+		if e.IsDir() {
+			groupDirs = append(groupDirs, e.Name())
+		}
+	}
+	if len(groupDirs) > 0 && !scoring {
+		report.unmapped("test groups of a pass-fail problem imported with 0 points")
+	}
+
+	for i, dir := range groupDirs {
+		dirPath := path.Join("data/secret", dir)
+		testdata, err := readKattisTestdata(fsys, dirPath, secret)
+		if err != nil {
+			return err
+		}
+		tests, err := readKattisTestDir(fsys, dirPath, report)
+		if err != nil {
+			return err
+		}
+		if sub, _ := fs.ReadDir(fsys, dirPath); hasDir(sub) {
+			report.unmapped("nested test groups in %s", dirPath)
+		}
+		ids := addTests(tests)
+		if dir == kattisUngroupedDir {
+			continue
+		}
+
+		points := 0.0
+		if scoring {
+			points = 1
+			if testdata.AcceptScore != nil {
+				points = *testdata.AcceptScore
+			}
+			flags := strings.Fields(testdata.GraderFlags)
+			if !containsString(flags, "min") && !containsString(flags, "max") {
+				points *= float64(len(ids))
+			}
+		}
+		if points != float64(int(points)) {
+			report.unmapped("group %s fractional points %g rounded down", dir, points)
+		}
+
+		groupID, subtask := i+1, i+1
+		if m := kattisGroupDirRegexp.FindStringSubmatch(dir); m != nil {
+			groupID, _ = strconv.Atoi(m[1])
+			subtask, _ = strconv.Atoi(m[2])
+		}
+		err = t.AddTestGroupWithID(groupID, int(points), false, ids, subtask)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readKattisValidator returns the source of a single file C++ output validator
+func readKattisValidator(fsys fs.FS, report *ImportReport) []byte {
+	sources := make([]string, 0)
+	_ = fs.WalkDir(fsys, "output_validators", func(p string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			sources = append(sources, p)
+		}
+		return nil
+	})
+	if len(sources) != 1 || (path.Ext(sources[0]) != ".cpp" && path.Ext(sources[0]) != ".cc") {
+		report.unmapped("custom output validator with files %v", sources)
+		return nil
+	}
+
+	source, err := fs.ReadFile(fsys, sources[0])
+	if err != nil {
+		report.unmapped("output validator %s: %v", sources[0], err)
+		return nil
+	}
+	report.unmapped("output validator %s imported as checker, its interface may differ", sources[0])
+	return source
+}
+
+func (t *Task) importKattisStatements(fsys fs.FS, report *ImportReport) error {
+	entries, err := fs.ReadDir(fsys, "problem_statement")
+	if err != nil {
+		return nil
+	}
+
+	statements := make(map[string]bool)
+	for _, e := range entries {
+		m := kattisStatementFileRegexp.FindStringSubmatch(e.Name())
+		if e.IsDir() || m == nil {
+			continue
+		}
+		statements[e.Name()] = true
+		lang := m[2]
+		if lang == "" {
+			lang = "en"
+		}
+		content, err := fs.ReadFile(fsys, path.Join("problem_statement", e.Name()))
+		if err != nil {
+			return fmt.Errorf("error reading statement: %w", err)
+		}
+
+		var intro string
+		var sections []headedSection
+		if m[3] == "md" {
+			intro, sections = splitMarkdownSections(string(content))
+		} else {
+			intro, sections = splitTexSections(string(content))
+			md, _, unsupported := texToMarkdown(intro)
+			intro = md
+			for i := range sections {
+				md, _, more := texToMarkdown(sections[i].body)
+				sections[i].body = md
+				unsupported = append(unsupported, more...)
+			}
+			for _, u := range unsupported {
+				report.unmapped("%s in %s kept as LaTeX", u, e.Name())
+			}
+		}
+
+		err = t.SetMarkdownStatement(lang, statementFromSections(intro, sections, e.Name(), report))
+		if err != nil {
+			return err
+		}
+	}
+
+	err = fs.WalkDir(fsys, "problem_statement", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel := strings.TrimPrefix(p, "problem_statement/")
+		if statements[rel] {
+			return nil
+		}
+		content, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return fmt.Errorf("error reading statement file: %w", err)
+		}
+		return t.AddAsset(rel, content)
+	})
+	return err
+}
+
+type headedSection struct {
+	heading string
+	body    string
+}
+
+var mdSectionHeadingRegexp = regexp.MustCompile(`(?m)^##\s+(.+?)\s*#*\s*$`)
+
+var texSectionRegexp = regexp.MustCompile(`\\section\*?\{([^}]*)\}`)
+
+var texProblemNameRegexp = regexp.MustCompile(`\\problemname\{[^}]*\}`)
+
+func splitMarkdownSections(md string) (string, []headedSection) {
+	return splitSections(md, mdSectionHeadingRegexp)
+}
+
+func splitTexSections(tex string) (string, []headedSection) {
+	return splitSections(texProblemNameRegexp.ReplaceAllString(tex, ""), texSectionRegexp)
+}
+
+func splitSections(s string, heading *regexp.Regexp) (string, []headedSection) {
+	locs := heading.FindAllStringSubmatchIndex(s, -1)
+	if len(locs) == 0 {
+		return s, nil
+	}
+	sections := make([]headedSection, 0, len(locs))
+	for i, loc := range locs {
+		end := len(s)
+		if i+1 < len(locs) {
+			end = locs[i+1][0]
+		}
+		sections = append(sections, headedSection{heading: s[loc[2]:loc[3]], body: s[loc[1]:end]})
+	}
+	return s[:locs[0][0]], sections
+}
+
+// statementFromSections assigns the sections to statement parts by their headings,
+// unknown headings become additional sections named after them.
+func statementFromSections(intro string, sections []headedSection, fname string, report *ImportReport) MarkdownStatement {
+	trim := func(md string) string {
+		md = strings.TrimSpace(md)
+		if md == "" {
+			return ""
+		}
+		return md + "\n"
+	}
+
+	st := MarkdownStatement{Story: trim(intro)}
+	for _, s := range sections {
+		body := trim(s.body)
+		name := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(s.heading)), " ", "_")
+		if part, ok := statementSectionHeadings[name]; ok {
+			name = part
+		}
+		switch name {
+		case "input":
+			st.Input = body
+		case "output":
+			st.Output = body
+		case "scoring":
+			st.Scoring = &body
+		case "notes":
+			st.Notes = &body
+		default:
+			if validateStatementSectionName(name) != nil {
+				report.unmapped("section %q of %s appended to the story", s.heading, fname)
+				st.Story += "\n## " + s.heading + "\n\n" + body
+				continue
+			}
+			if st.Sections == nil {
+				st.Sections = map[string]string{}
+			}
+			st.Sections[name] = body
+		}
+	}
+	return st
+}
+
+func sortedKeys(m map[string]any) []string {
+	res := make([]string, 0, len(m))
+	for k := range m {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+	return res
+}
+
+func hasDir(entries []fs.DirEntry) bool {
+	for _, e := range entries {
+		if e.IsDir() {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
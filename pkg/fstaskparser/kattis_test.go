@@ -0,0 +1,119 @@
+package fstaskparser_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/programme-lv/fs-task-format-parser/pkg/fstaskparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var kattisPackagePath = filepath.Join(prjRootPath, "testdata", "kattis-aplusb")
+
+func TestImportingKattisPackage(t *testing.T) {
+	task, report, err := fstaskparser.ImportKattis(kattisPackagePath)
+	require.NoErrorf(t, err, "failed to import package: %v", err)
+
+	assert.Equal(t, "A plus B", task.GetTaskName())
+	assert.Equal(t, 512, task.GetMemoryLimitInMegabytes())
+	assert.Equal(t, []string{"Alice", "Bob"}, task.GetTaskAuthors())
+	assert.Equal(t, "Example Contest 2024", task.GetOriginOlympiad())
+	assert.Equal(t, []string{"implementation", "math"}, task.GetProblemTags())
+
+	tests := task.GetTestsSortedByID()
+	require.Equal(t, 4, len(tests))
+	assert.Equal(t, []byte("1000000000 1000000000\n"), tests[2].Input)
+	assert.Equal(t, "big1", task.GetTestFilenameFromID(3))
+
+	examples := task.GetExamples()
+	require.Equal(t, 1, len(examples))
+	assert.Equal(t, []byte("1 2\n"), examples[0].Input)
+	assert.Equal(t, "1", *examples[0].FName)
+
+	require.Equal(t, []int{1, 2}, task.GetTestGroupIDs())
+	assert.Equal(t, fstaskparser.TestGroupInfo{GroupID: 1, Points: 40, Public: false, TestIDs: []int{1, 2}, Subtask: 1}, task.GetInfoOnTestGroup(1))
+	assert.Equal(t, fstaskparser.TestGroupInfo{GroupID: 2, Points: 60, Public: false, TestIDs: []int{3, 4}, Subtask: 2}, task.GetInfoOnTestGroup(2))
+
+	st, err := task.GetMarkdownStatement("en")
+	require.NoErrorf(t, err, "failed to get statement: %v", err)
+	assert.Equal(t, "Given two integers $a$ and $b$, compute their sum — the **only** number you need to print.\n\n![](sum.png)\n", st.Story)
+	assert.Equal(t, "The only line contains two integers $a$ and $b$ ($-10^9 \\le a, b \\le 10^9$).\n", st.Input)
+	assert.Equal(t, "Print `a+b`.\n", st.Output)
+	require.NotNil(t, st.Scoring)
+	assert.Equal(t, "- Group 1 (40 points): $0 \\le a, b \\le 100$;\n- Group 2 (60 points): no additional constraints.\n", *st.Scoring)
+	assert.Empty(t, task.FindMissingAssetReferences())
+
+	assert.Contains(t, string(task.GetCheckerSource()), "return 42;")
+
+	assert.Equal(t, []string{
+		"problem.yaml key license",
+		"problem.yaml limit time_multiplier",
+		"no time limit, problemtools derives it from the judge solutions",
+		"output validator output_validators/validator/validator.cpp imported as checker, its interface may differ",
+		"directory input_validators",
+		"directory submissions",
+	}, report.Unmapped)
+}
+
+func TestImportingKattisPackageWithEmptyName(t *testing.T) {
+	fsys := fstest.MapFS{
+		"problem.yaml":       {Data: []byte("name: {}\nlimits:\n  time_limit: 2\n")},
+		"data/secret/01.in":  {Data: []byte("1 2\n")},
+		"data/secret/01.ans": {Data: []byte("3\n")},
+	}
+	task, _, err := fstaskparser.ImportKattisFS(fsys)
+	require.NoErrorf(t, err, "failed to import package: %v", err)
+	assert.Equal(t, "", task.GetTaskName())
+	assert.Equal(t, 1, len(task.GetTestsSortedByID()))
+}
+
+func TestExportingTaskToKattis(t *testing.T) {
+	task, err := fstaskparser.Read(testTaskPath)
+	require.NoErrorf(t, err, "failed to read task: %v", err)
+
+	tmpDirectory, err := os.MkdirTemp("", "fstaskparser-test-")
+	require.NoErrorf(t, err, "failed to create temporary directory: %v", err)
+	defer os.RemoveAll(tmpDirectory)
+
+	outputDirectory := filepath.Join(tmpDirectory, "kvadrputekl")
+	report, err := task.ExportKattis(outputDirectory)
+	require.NoErrorf(t, err, "failed to export task: %v", err)
+	assert.Contains(t, report.Dropped, "test group 1 is public, Kattis shows only data/sample")
+
+	testdata, err := os.ReadFile(filepath.Join(outputDirectory, "data", "secret", "group02-subtask02", "testdata.yaml"))
+	require.NoErrorf(t, err, "failed to read testdata.yaml: %v", err)
+	assert.Equal(t, "on_reject: break\ngrader_flags: min\naccept_score: 8\nrange: 0 8\n", string(testdata))
+
+	input, err := os.ReadFile(filepath.Join(outputDirectory, "data", "secret", "group01-subtask01", "kp01a.in"))
+	require.NoErrorf(t, err, "failed to read test input: %v", err)
+	assert.Equal(t, task.GetTestsSortedByID()[0].Input, input)
+
+	imported, _, err := fstaskparser.ImportKattis(outputDirectory)
+	require.NoErrorf(t, err, "failed to import exported package: %v", err)
+	assert.Equal(t, task.GetTaskName(), imported.GetTaskName())
+	assert.Equal(t, task.GetCPUTimeLimitInSeconds(), imported.GetCPUTimeLimitInSeconds())
+	assert.Equal(t, task.GetMemoryLimitInMegabytes(), imported.GetMemoryLimitInMegabytes())
+	assert.Equal(t, task.GetTestsSortedByID(), imported.GetTestsSortedByID())
+	require.Equal(t, task.GetTestGroupIDs(), imported.GetTestGroupIDs())
+	for _, id := range task.GetTestGroupIDs() {
+		expected := task.GetInfoOnTestGroup(id)
+		expected.Public = false
+		assert.Equal(t, expected, imported.GetInfoOnTestGroup(id))
+	}
+	require.Equal(t, len(task.GetExamples()), len(imported.GetExamples()))
+	for i, e := range task.GetExamples() {
+		assert.Equal(t, e.Input, imported.GetExamples()[i].Input)
+		assert.Equal(t, e.Output, imported.GetExamples()[i].Output)
+	}
+
+	expectedSt, err := task.GetMarkdownStatement("lv")
+	require.NoErrorf(t, err, "failed to get statement: %v", err)
+	st, err := imported.GetMarkdownStatement("lv")
+	require.NoErrorf(t, err, "failed to get imported statement: %v", err)
+	assert.Equal(t, expectedSt.Input, st.Input)
+	assert.Equal(t, expectedSt.Output, st.Output)
+	assert.Equal(t, len(task.GetAssets()), len(imported.GetAssets()))
+}
@@ -0,0 +1,46 @@
+package fstaskparser
+
+import (
+	"fmt"
+
+	"golang.org/x/text/language"
+)
+
+// normalizeLanguageCode validates a BCP 47 language tag and returns its
+// canonical form, e.g. "en-us" becomes "en-US".
+func normalizeLanguageCode(lang string) (string, error) {
+	tag, err := language.Parse(lang)
+	if err != nil {
+		return "", fmt.Errorf("invalid language code %q: %w", lang, err)
+	}
+	return tag.String(), nil
+}
+
+// lookupLanguageCode normalizes a language used to look up a statement.
+// Invalid tags are kept as they are, as Read keeps such directories.
+func lookupLanguageCode(lang string) string {
+	if normalized, err := normalizeLanguageCode(lang); err == nil {
+		return normalized
+	}
+	return lang
+}
+
+// statementLanguageDiagnostics reports statement directories whose name
+// is not a valid BCP 47 language tag.
+func (t *Task) statementLanguageDiagnostics() []ReadDiagnostic {
+	res := make([]ReadDiagnostic, 0)
+	for _, st := range t.mdStatements {
+		if st.Language == nil {
+			continue
+		}
+		if _, err := normalizeLanguageCode(*st.Language); err != nil {
+			res = append(res, ReadDiagnostic{Path: "statements/md/" + *st.Language, Message: err.Error()})
+		}
+	}
+	for lang := range t.pdfStatements {
+		if _, err := normalizeLanguageCode(lang); err != nil {
+			res = append(res, ReadDiagnostic{Path: "statements/pdf/" + lang + ".pdf", Message: err.Error()})
+		}
+	}
+	return res
+}
@@ -0,0 +1,318 @@
+package fstaskparser
+
+import (
+	"sort"
+	"strings"
+)
+
+// texToMarkdown converts the LaTeX subset used in Polygon statement sections
+// to Markdown. Math is copied verbatim. It returns the images referenced by
+// \includegraphics and the commands and environments it could not convert,
+// which are left in the output as they are.
+func texToMarkdown(tex string) (md string, images []string, unsupported []string) {
+	c := &texConverter{unsupported: map[string]bool{}}
+	out := c.convert(tex)
+
+	// the indentation of the source is dropped while converting, so only
+	// trailing spaces are left to trim
+	lines := strings.Split(out, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+		if strings.HasSuffix(line, "  ") && strings.TrimSpace(line) != "" {
+			lines[i] += "  " // keep hard line breaks
+		}
+	}
+	out = strings.Join(lines, "\n")
+	for strings.Contains(out, "\n\n\n") {
+		out = strings.ReplaceAll(out, "\n\n\n", "\n\n")
+	}
+
+	for cmd := range c.unsupported {
+		unsupported = append(unsupported, cmd)
+	}
+	sort.Strings(unsupported)
+	return strings.TrimSpace(out) + "\n", c.images, unsupported
+}
+
+type texConverter struct {
+	lists       []string // "-" for itemize, "1." for enumerate
+	images      []string
+	unsupported map[string]bool
+	code        bool // converting a \texttt argument, where -- and << are not ligatures
+}
+
+var texFormatting = map[string][2]string{
+	"textbf": {"**", "**"},
+	"bf":     {"**", "**"},
+	"textit": {"*", "*"},
+	"emph":   {"*", "*"},
+	"it":     {"*", "*"},
+	"texttt": {"`", "`"},
+	"tt":     {"`", "`"},
+	"t":      {"`", "`"},
+}
+
+// texCodeFormatting are the formatting commands whose argument is code
+var texCodeFormatting = map[string]bool{
+	"texttt": true,
+	"tt":     true,
+	"t":      true,
+}
+
+var texSymbols = map[string]string{
+	"ldots":     "…",
+	"dots":      "…",
+	"dash":      "—",
+	"LaTeX":     "LaTeX",
+	"TeX":       "TeX",
+	"noindent":  "",
+	"medskip":   "",
+	"bigskip":   "",
+	"smallskip": "",
+	"newline":   "  \n",
+	"par":       "\n\n",
+}
+
+var texIgnoredEnvironments = map[string]bool{
+	"center":     true,
+	"flushleft":  true,
+	"flushright": true,
+	"figure":     true,
+}
+
+func (c *texConverter) convert(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		switch {
+		case !c.code && (i == 0 || s[i-1] == '\n') && (s[i] == ' ' || s[i] == '\t'):
+			// drop the indentation of the source so that Markdown does not see code blocks
+			i = skipSpaces(s, i)
+		case s[i] == '%':
+			i = nextLineStart(s, i)
+		case s[i] == '$':
+			end := texMathEnd(s, i)
+			b.WriteString(s[i:end])
+			i = end
+		case c.code && isTexLigatureStart(s[i]):
+			b.WriteByte(s[i])
+			i++
+		case strings.HasPrefix(s[i:], "---"):
+			b.WriteString("—")
+			i += 3
+		case strings.HasPrefix(s[i:], "--"):
+			b.WriteString("–")
+			i += 2
+		case strings.HasPrefix(s[i:], "``"):
+			b.WriteString("“")
+			i += 2
+		case strings.HasPrefix(s[i:], "''"):
+			b.WriteString("”")
+			i += 2
+		case strings.HasPrefix(s[i:], "<<"):
+			b.WriteString("«")
+			i += 2
+		case strings.HasPrefix(s[i:], ">>"):
+			b.WriteString("»")
+			i += 2
+		case s[i] == '~':
+			b.WriteString(" ")
+			i++
+		case s[i] == '\\':
+			i = c.convertCommand(s, i, &b)
+		case s[i] == '{' || s[i] == '}':
+			// grouping braces
+			i++
+		default:
+			b.WriteByte(s[i])
+			i++
+		}
+	}
+	return b.String()
+}
+
+// convertCommand converts the command starting at s[i] and returns the index after it
+func (c *texConverter) convertCommand(s string, i int, b *strings.Builder) int {
+	j := i + 1
+	for j < len(s) && isASCIILetter(s[j]) {
+		j++
+	}
+	if j == i+1 {
+		// control symbol
+		if j >= len(s) {
+			return j
+		}
+		switch s[j] {
+		case '\\':
+			b.WriteString("  \n")
+		case '$', '_', '#', '*':
+			b.WriteString("\\" + string(s[j]))
+		case ',', ' ':
+			b.WriteByte(' ')
+		default:
+			b.WriteByte(s[j])
+		}
+		return j + 1
+	}
+
+	name := s[i+1 : j]
+	if f, ok := texFormatting[name]; ok {
+		arg, end := texBraceArg(s, j)
+		if end < 0 {
+			return j // old style switches such as {\bf text}
+		}
+		code := c.code
+		c.code = code || texCodeFormatting[name]
+		b.WriteString(f[0] + c.convert(arg) + f[1])
+		c.code = code
+		return end
+	}
+	if symbol, ok := texSymbols[name]; ok {
+		b.WriteString(symbol)
+		return j
+	}
+
+	switch {
+	case name == "underline" || name == "textrm":
+		arg, end := texBraceArg(s, j)
+		if end < 0 {
+			return j
+		}
+		b.WriteString(c.convert(arg))
+		return end
+	case name == "section" || name == "subsection" || name == "subsubsection":
+		arg, end := texBraceArg(s, j)
+		if end < 0 {
+			break
+		}
+		b.WriteString("\n\n### " + c.convert(arg) + "\n\n")
+		return end
+	case name == "includegraphics":
+		k := skipTexOptionalArg(s, j)
+		arg, end := texBraceArg(s, k)
+		if end < 0 {
+			break
+		}
+		c.images = append(c.images, arg)
+		b.WriteString("![](" + arg + ")")
+		return end
+	case name == "item":
+		if !strings.HasSuffix(strings.TrimRight(b.String(), " \t"), "\n") {
+			b.WriteString("\n")
+		}
+		if len(c.lists) > 0 {
+			// nested items are indented by the width of the outer markers
+			indent := 0
+			for _, marker := range c.lists[:len(c.lists)-1] {
+				indent += len(marker) + 1
+			}
+			b.WriteString(strings.Repeat(" ", indent) + c.lists[len(c.lists)-1] + " ")
+		} else {
+			b.WriteString("- ")
+		}
+		return skipSpaces(s, j)
+	case name == "begin" || name == "end":
+		env, end := texBraceArg(s, j)
+		if end < 0 {
+			break
+		}
+		switch {
+		case env == "itemize" || env == "enumerate":
+			if name == "begin" {
+				marker := "-"
+				if env == "enumerate" {
+					marker = "1."
+				}
+				c.lists = append(c.lists, marker)
+			} else if len(c.lists) > 0 {
+				c.lists = c.lists[:len(c.lists)-1]
+			}
+			b.WriteString("\n\n")
+			return end
+		case texIgnoredEnvironments[env]:
+			b.WriteString("\n\n")
+			return end
+		}
+		// keep the whole environment as it is
+		c.unsupported["environment "+env] = true
+		if name == "begin" {
+			closing := "\\end{" + env + "}"
+			if k := strings.Index(s[end:], closing); k >= 0 {
+				end += k + len(closing)
+			}
+		}
+		b.WriteString(s[i:end])
+		return end
+	}
+
+	c.unsupported["\\"+name] = true
+	b.WriteString(s[i:j])
+	return j
+}
+
+// texMathEnd returns the index after the `$...$` or `$$...$$` starting at s[i]
+func texMathEnd(s string, i int) int {
+	delim := "$"
+	if strings.HasPrefix(s[i:], "$$") {
+		delim = "$$"
+	}
+	for j := i + len(delim); j < len(s); j++ {
+		if s[j] == '\\' {
+			j++
+			continue
+		}
+		if strings.HasPrefix(s[j:], delim) {
+			return j + len(delim)
+		}
+	}
+	return len(s)
+}
+
+// texBraceArg returns the contents of the {...} argument starting at or
+// after s[i] and the index after it, or -1 if there is none.
+func texBraceArg(s string, i int) (string, int) {
+	i = skipSpaces(s, i)
+	if i >= len(s) || s[i] != '{' {
+		return "", -1
+	}
+	depth := 0
+	for j := i; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return s[i+1 : j], j + 1
+			}
+		}
+	}
+	return "", -1
+}
+
+func skipTexOptionalArg(s string, i int) int {
+	k := skipSpaces(s, i)
+	if k < len(s) && s[k] == '[' {
+		if end := strings.IndexByte(s[k:], ']'); end >= 0 {
+			return k + end + 1
+		}
+	}
+	return i
+}
+
+func skipSpaces(s string, i int) int {
+	for i < len(s) && (s[i] == ' ' || s[i] == '\t') {
+		i++
+	}
+	return i
+}
+
+// isTexLigatureStart tells whether c starts a dash, quote or guillemet ligature
+func isTexLigatureStart(c byte) bool {
+	return c == '-' || c == '`' || c == '\'' || c == '<' || c == '>'
+}
+
+func isASCIILetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
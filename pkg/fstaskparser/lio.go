@@ -0,0 +1,500 @@
+package fstaskparser
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Confidence tells how sure a heuristic importer is about a guess.
+type Confidence string
+
+const (
+	ConfidenceHigh   Confidence = "high"
+	ConfidenceMedium Confidence = "medium"
+	ConfidenceLow    Confidence = "low"
+)
+
+var confidenceRank = map[Confidence]int{ConfidenceLow: 0, ConfidenceMedium: 1, ConfidenceHigh: 2}
+
+// Guess is one conclusion a heuristic importer drew from the file layout.
+type Guess struct {
+	Subject    string // what was guessed, e.g. "tests", "groups", "points"
+	Confidence Confidence
+	Detail     string
+}
+
+// ConfidenceReport lists the guesses of a heuristic import, so that the
+// result can be reviewed before it is stored, along with the unmapped files.
+type ConfidenceReport struct {
+	ImportReport
+	Guesses []Guess
+}
+
+func (r *ConfidenceReport) guess(subject string, confidence Confidence, format string, args ...any) {
+	r.Guesses = append(r.Guesses, Guess{
+		Subject:    subject,
+		Confidence: confidence,
+		Detail:     fmt.Sprintf(format, args...),
+	})
+}
+
+// Confidence returns the lowest confidence of all guesses, high if there are none
+func (r *ConfidenceReport) Confidence() Confidence {
+	res := ConfidenceHigh
+	for _, g := range r.Guesses {
+		if confidenceRank[g.Confidence] < confidenceRank[res] {
+			res = g.Confidence
+		}
+	}
+	return res
+}
+
+func (r *ConfidenceReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s import, overall confidence %s:", r.Format, r.Confidence())
+	for _, g := range r.Guesses {
+		fmt.Fprintf(&b, "\n- [%s] %s: %s", g.Confidence, g.Subject, g.Detail)
+	}
+	for _, u := range r.Unmapped {
+		fmt.Fprintf(&b, "\n- unmapped: %s", u)
+	}
+	return b.String()
+}
+
+// lioTestFileRegexps recognise legacy test file names such as kp.i01a and
+// kp.o01a, or kp01a.in and kp01a.out. Test number 0 holds the examples.
+var lioTestFileRegexps = []*regexp.Regexp{
+	regexp.MustCompile(`^(?P<prefix>[A-Za-z_]*)\.(?P<io>[io])(?P<num>[0-9]+)(?P<letter>[a-z]?)$`),
+	regexp.MustCompile(`^(?P<prefix>[A-Za-z_]*?)(?P<num>[0-9]+)(?P<letter>[a-z]?)\.(?P<io>in|out|ans|i|o)$`),
+}
+
+var lioTestDirs = []string{"tests", "testi", "test", "."}
+
+var lioPointsFiles = map[string]bool{
+	"punkti.txt": true,
+	"punkti":     true,
+	"points.txt": true,
+	"points":     true,
+}
+
+// lioPointsLineRegexp matches "<group> <points>" and "<from>-<to> <points>" lines
+var lioPointsLineRegexp = regexp.MustCompile(`^\s*([0-9]+)(?:\s*-\s*([0-9]+))?\s*[:=\s]\s*([0-9]+)\s*$`)
+
+var lioTimeLimitRegexp = regexp.MustCompile(`(?i)(laik\S*|time)[^0-9\n]*([0-9]+(?:[.,][0-9]+)?)\s*(s|sek|sec)`)
+
+var lioMemoryLimitRegexp = regexp.MustCompile(`(?i)(atmi\S*|memory)[^0-9\n]*([0-9]+)\s*(mb|mib|megabait)`)
+
+var lioCheckerRegexp = regexp.MustCompile(`(?i)^(check|checker|parbaud|p[aā]rbaud)[^/]*\.(cpp|cc)$`)
+
+type lioTest struct {
+	num    int
+	letter string
+	name   string
+	input  []byte
+	answer []byte
+}
+
+// ImportLegacyLIO reads an old LIO task folder or zip that has no
+// problem.toml, recognising tests such as tests/kp.i01a and tests/kp.o01a,
+// groups from the letter suffixes, a points file, PDF statements such as
+// uzd.lv.pdf and examples from test number 0.
+func ImportLegacyLIO(packagePath string) (*Task, *ConfidenceReport, error) {
+	fsys, closePkg, err := openPackage(packagePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closePkg()
+
+	name := strings.TrimSuffix(filepath.Base(packagePath), filepath.Ext(packagePath))
+	return ImportLegacyLIOFS(fsys, name)
+}
+
+// ImportLegacyLIOFS reads an old LIO task rooted at fsys, taskName is used
+// when no better name is found.
+func ImportLegacyLIOFS(fsys fs.FS, taskName string) (*Task, *ConfidenceReport, error) {
+	report := &ConfidenceReport{ImportReport: ImportReport{Format: "lio-legacy"}}
+	used := make(map[string]bool)
+
+	t, err := NewTask(taskName)
+	if err != nil {
+		return nil, nil, err
+	}
+	report.guess("name", ConfidenceLow, "task name %q taken from the folder name", taskName)
+
+	tests, testDir, err := readLIOTests(fsys, used, report)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(tests) == 0 {
+		return nil, nil, fmt.Errorf("no legacy test files found")
+	}
+
+	groupNums := make([]int, 0)
+	groupTests := make(map[int][]int)
+	lettered := false
+	for _, lt := range tests {
+		if lt.num == 0 {
+			name := lt.name
+			t.examples = append(t.examples, example{Input: lt.input, Output: lt.answer, Name: &name})
+			continue
+		}
+		id := t.AddTest(lt.input, lt.answer)
+		t.AssignFilenameToTest(lt.name, id)
+		if _, ok := groupTests[lt.num]; !ok {
+			groupNums = append(groupNums, lt.num)
+		}
+		groupTests[lt.num] = append(groupTests[lt.num], id)
+		lettered = lettered || lt.letter != ""
+	}
+	report.guess("tests", ConfidenceHigh, "%d tests read from %s", len(t.tests), testDir)
+	if len(t.examples) > 0 {
+		report.guess("examples", ConfidenceMedium, "%d tests numbered 0 used as examples", len(t.examples))
+	} else {
+		report.guess("examples", ConfidenceLow, "no tests numbered 0, the task has no examples")
+	}
+	if lettered {
+		report.guess("groups", ConfidenceHigh, "%d groups from the test number, tests of a group differ by the letter suffix", len(groupNums))
+	} else {
+		report.guess("groups", ConfidenceMedium, "no letter suffixes, every test is its own group")
+	}
+
+	points, err := readLIOPoints(fsys, groupNums, used, report)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, num := range groupNums {
+		err = t.AddTestGroupWithID(num, points[num], false, groupTests[num], num)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	report.guess("subtasks", ConfidenceMedium, "every group is its own subtask numbered as the group")
+
+	err = t.importLIOStatements(fsys, used, report)
+	if err != nil {
+		return nil, nil, err
+	}
+	t.importLIOLimits(fsys, used, report)
+
+	err = fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || used[p] {
+			return err
+		}
+		if t.checker == nil && lioCheckerRegexp.MatchString(path.Base(p)) {
+			t.checker, err = fs.ReadFile(fsys, p)
+			if err != nil {
+				return fmt.Errorf("error reading checker: %w", err)
+			}
+			report.guess("checker", ConfidenceMedium, "%s used as checker", p)
+			return nil
+		}
+		report.unmapped("file %s", p)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return t, report, nil
+}
+
+// hasLIOTestFiles tells from the file names alone whether a directory
+// holds an input and answer pair of legacy test files
+func hasLIOTestFiles(fsys fs.FS) bool {
+	for _, dir := range lioTestDirs {
+		entries, err := fs.ReadDir(fsys, dir)
+		if err != nil {
+			continue
+		}
+		inputs := make(map[string]bool)
+		outputs := make(map[string]bool)
+		for _, e := range entries {
+			if e.IsDir() {
+				continue
+			}
+			for _, re := range lioTestFileRegexps {
+				m := re.FindStringSubmatch(e.Name())
+				if m == nil {
+					continue
+				}
+				num, _ := strconv.Atoi(m[re.SubexpIndex("num")])
+				key := fmt.Sprintf("%s/%d%s", m[re.SubexpIndex("prefix")], num, m[re.SubexpIndex("letter")])
+				if strings.HasPrefix(m[re.SubexpIndex("io")], "i") {
+					inputs[key] = true
+				} else {
+					outputs[key] = true
+				}
+				break
+			}
+		}
+		for key := range inputs {
+			if outputs[key] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// readLIOTests reads the tests of the first directory with recognised test
+// files, sorted by number and letter
+func readLIOTests(fsys fs.FS, used map[string]bool, report *ConfidenceReport) ([]lioTest, string, error) {
+	for _, dir := range lioTestDirs {
+		entries, err := fs.ReadDir(fsys, dir)
+		if err != nil {
+			continue
+		}
+
+		type pair struct {
+			lioTest
+			inPath, outPath string
+		}
+		pairs := make(map[string]*pair)
+		prefixes := make(map[string]bool)
+		for _, e := range entries {
+			if e.IsDir() {
+				continue
+			}
+			for _, re := range lioTestFileRegexps {
+				m := re.FindStringSubmatch(e.Name())
+				if m == nil {
+					continue
+				}
+				prefix := m[re.SubexpIndex("prefix")]
+				num, _ := strconv.Atoi(m[re.SubexpIndex("num")])
+				letter := m[re.SubexpIndex("letter")]
+				key := fmt.Sprintf("%s/%d%s", prefix, num, letter)
+				if pairs[key] == nil {
+					pairs[key] = &pair{lioTest: lioTest{
+						num:    num,
+						letter: letter,
+						name:   fmt.Sprintf("%s%02d%s", prefix, num, letter),
+					}}
+				}
+				p := path.Join(dir, e.Name())
+				if io := m[re.SubexpIndex("io")]; strings.HasPrefix(io, "i") {
+					pairs[key].inPath = p
+				} else {
+					pairs[key].outPath = p
+				}
+				prefixes[prefix] = true
+				break
+			}
+		}
+		if len(pairs) == 0 {
+			continue
+		}
+		if len(prefixes) > 1 {
+			report.guess("tests", ConfidenceLow, "test files in %s have several prefixes, they are merged by number", dir)
+		}
+
+		keys := make([]string, 0, len(pairs))
+		for key := range pairs {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		res := make([]lioTest, 0, len(pairs))
+		for _, key := range keys {
+			p := pairs[key]
+			if p.inPath == "" || p.outPath == "" {
+				report.unmapped("test %s without input or answer", p.name)
+				continue
+			}
+			var err error
+			p.input, err = fs.ReadFile(fsys, p.inPath)
+			if err != nil {
+				return nil, "", fmt.Errorf("error reading test input: %w", err)
+			}
+			p.answer, err = fs.ReadFile(fsys, p.outPath)
+			if err != nil {
+				return nil, "", fmt.Errorf("error reading test answer: %w", err)
+			}
+			used[p.inPath] = true
+			used[p.outPath] = true
+			res = append(res, p.lioTest)
+		}
+		sort.Slice(res, func(i, j int) bool {
+			if res[i].num != res[j].num {
+				return res[i].num < res[j].num
+			}
+			return res[i].letter < res[j].letter
+		})
+		return res, dir, nil
+	}
+	return nil, "", nil
+}
+
+// readLIOPoints reads the points of every group from a plain text points
+// file, either "<group> <points>" lines or one line of points in group order
+func readLIOPoints(fsys fs.FS, groupNums []int, used map[string]bool, report *ConfidenceReport) (map[int]int, error) {
+	res := make(map[int]int)
+	var pointsPath string
+	_ = fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() && pointsPath == "" && lioPointsFiles[strings.ToLower(path.Base(p))] {
+			pointsPath = p
+		}
+		return nil
+	})
+	if pointsPath == "" {
+		report.guess("points", ConfidenceLow, "no points file, every group has 0 points")
+		return res, nil
+	}
+	content, err := fs.ReadFile(fsys, pointsPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading points file: %w", err)
+	}
+	used[pointsPath] = true
+
+	lines := make([]string, 0)
+	for _, line := range strings.Split(string(content), "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	known := make(map[int]bool, len(groupNums))
+	for _, num := range groupNums {
+		known[num] = true
+	}
+
+	// "3 8" with two groups is a list of points unless 3 is a group
+	if fields := strings.Fields(strings.Join(lines, " ")); len(lines) == 1 && len(fields) == len(groupNums) {
+		first, _ := strconv.Atoi(fields[0])
+		if len(fields) != 2 || !known[first] {
+			return readLIOPointsList(fields, groupNums, pointsPath, report), nil
+		}
+	}
+
+	for _, line := range lines {
+		m := lioPointsLineRegexp.FindStringSubmatch(line)
+		if m == nil {
+			report.guess("points", ConfidenceLow, "line %q of %s not understood", strings.TrimSpace(line), pointsPath)
+			continue
+		}
+		from, _ := strconv.Atoi(m[1])
+		to := from
+		if m[2] != "" {
+			to, _ = strconv.Atoi(m[2])
+		}
+		p, _ := strconv.Atoi(m[3])
+		for num := from; num <= to; num++ {
+			if !known[num] {
+				report.guess("points", ConfidenceLow, "%s gives points to group %d without tests", pointsPath, num)
+				continue
+			}
+			res[num] = p
+		}
+	}
+	missing := make([]string, 0)
+	for _, num := range groupNums {
+		if _, ok := res[num]; !ok {
+			missing = append(missing, strconv.Itoa(num))
+		}
+	}
+	if len(missing) > 0 {
+		report.guess("points", ConfidenceLow, "no points for groups %s in %s", strings.Join(missing, ", "), pointsPath)
+	} else {
+		report.guess("points", ConfidenceHigh, "points of every group from %s", pointsPath)
+	}
+	return res, nil
+}
+
+// readLIOPointsList assigns a list of points to the groups in order
+func readLIOPointsList(fields []string, groupNums []int, pointsPath string, report *ConfidenceReport) map[int]int {
+	res := make(map[int]int)
+	for i, f := range fields {
+		p, err := strconv.Atoi(f)
+		if err != nil {
+			report.guess("points", ConfidenceLow, "%s is not a list of points, every group has 0 points", pointsPath)
+			return map[int]int{}
+		}
+		res[groupNums[i]] = p
+	}
+	report.guess("points", ConfidenceMedium, "points of the groups in order from %s", pointsPath)
+	return res
+}
+
+// importLIOStatements reads PDF statements named <anything>.<lang>.pdf, a
+// PDF without a language is taken to be in Latvian
+func (t *Task) importLIOStatements(fsys fs.FS, used map[string]bool, report *ConfidenceReport) error {
+	pdfs := make([]string, 0)
+	_ = fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() && strings.EqualFold(path.Ext(p), ".pdf") {
+			pdfs = append(pdfs, p)
+		}
+		return nil
+	})
+
+	for _, p := range pdfs {
+		base := strings.TrimSuffix(path.Base(p), path.Ext(p))
+		lang := "lv"
+		confidence := ConfidenceMedium
+		if ext := path.Ext(base); ext != "" {
+			if l, err := normalizeLanguageCode(ext[1:]); err == nil {
+				lang = l
+				confidence = ConfidenceHigh
+			}
+		}
+		if _, ok := t.pdfStatements[lang]; ok {
+			report.guess("statements", ConfidenceLow, "%s skipped, there already is a statement in %s", p, lang)
+			continue
+		}
+		content, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return fmt.Errorf("error reading pdf statement: %w", err)
+		}
+		t.pdfStatements[lang] = content
+		used[p] = true
+		report.guess("statements", confidence, "%s used as the %s statement", p, lang)
+	}
+	if len(pdfs) == 0 {
+		report.guess("statements", ConfidenceLow, "no pdf statement found")
+	}
+	return nil
+}
+
+// importLIOLimits looks for the limits in the text files of the task
+func (t *Task) importLIOLimits(fsys fs.FS, used map[string]bool, report *ConfidenceReport) {
+	foundTime, foundMemory := false, false
+	_ = fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || used[p] || path.Ext(p) != ".txt" {
+			return nil
+		}
+		content, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return nil
+		}
+		if m := lioTimeLimitRegexp.FindStringSubmatch(string(content)); m != nil && !foundTime {
+			seconds, err := strconv.ParseFloat(strings.ReplaceAll(m[2], ",", "."), 64)
+			if err == nil {
+				t.cpuTimeSeconds = seconds
+				foundTime = true
+				used[p] = true
+				report.guess("limits", ConfidenceMedium, "time limit %g s from %s", seconds, p)
+			}
+		}
+		if m := lioMemoryLimitRegexp.FindStringSubmatch(string(content)); m != nil && !foundMemory {
+			megabytes, err := strconv.Atoi(m[2])
+			if err == nil {
+				t.memoryMegabytes = megabytes
+				foundMemory = true
+				used[p] = true
+				report.guess("limits", ConfidenceMedium, "memory limit %d MB from %s", megabytes, p)
+			}
+		}
+		return nil
+	})
+	if !foundTime {
+		report.guess("limits", ConfidenceLow, "no time limit found, using %g s", t.cpuTimeSeconds)
+	}
+	if !foundMemory {
+		report.guess("limits", ConfidenceLow, "no memory limit found, using %d MB", t.memoryMegabytes)
+	}
+}
@@ -0,0 +1,99 @@
+package fstaskparser_test
+
+import (
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/programme-lv/fs-task-format-parser/pkg/fstaskparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var legacyLIOTaskPath = filepath.Join(prjRootPath, "testdata", "lio-kvadrputekl")
+
+func TestImportingLegacyLIOTask(t *testing.T) {
+	task, report, err := fstaskparser.ImportLegacyLIO(legacyLIOTaskPath)
+	require.NoErrorf(t, err, "failed to import task: %v", err)
+
+	expected, err := fstaskparser.Read(testTaskPath)
+	require.NoErrorf(t, err, "failed to read task: %v", err)
+
+	assert.Equal(t, "lio-kvadrputekl", task.GetTaskName())
+	assert.Equal(t, expected.GetCPUTimeLimitInSeconds(), task.GetCPUTimeLimitInSeconds())
+	assert.Equal(t, expected.GetMemoryLimitInMegabytes(), task.GetMemoryLimitInMegabytes())
+	assert.Equal(t, expected.GetTestsSortedByID(), task.GetTestsSortedByID())
+	assert.Equal(t, "kp02a", task.GetTestFilenameFromID(4))
+
+	require.Equal(t, expected.GetTestGroupIDs(), task.GetTestGroupIDs())
+	for _, id := range expected.GetTestGroupIDs() {
+		info := expected.GetInfoOnTestGroup(id)
+		info.Public = false
+		assert.Equal(t, info, task.GetInfoOnTestGroup(id))
+	}
+
+	examples := task.GetExamples()
+	require.Equal(t, 1, len(examples))
+	assert.Equal(t, expected.GetExamples()[0].Input, examples[0].Input)
+	assert.Equal(t, "kp00", *examples[0].FName)
+
+	pdf, err := task.GetPDFStatement("lv")
+	require.NoErrorf(t, err, "failed to get pdf statement: %v", err)
+	expectedPDF, err := expected.GetPDFStatement("lv")
+	require.NoErrorf(t, err, "failed to get pdf statement: %v", err)
+	assert.Equal(t, expectedPDF, pdf)
+
+	assert.Equal(t, fstaskparser.ConfidenceLow, report.Confidence())
+	assert.Contains(t, report.Guesses, fstaskparser.Guess{
+		Subject:    "points",
+		Confidence: fstaskparser.ConfidenceHigh,
+		Detail:     "points of every group from punkti.txt",
+	})
+	assert.Contains(t, report.Guesses, fstaskparser.Guess{
+		Subject:    "limits",
+		Confidence: fstaskparser.ConfidenceMedium,
+		Detail:     "time limit 0.5 s from apraksts.txt",
+	})
+	assert.Equal(t, []string{"file sol/kp.cpp"}, report.Unmapped)
+}
+
+func TestImportingLegacyLIOTaskWithoutLetters(t *testing.T) {
+	fsys := fstest.MapFS{
+		"summa01.in":    {Data: []byte("1 2\n")},
+		"summa01.out":   {Data: []byte("3\n")},
+		"summa02.in":    {Data: []byte("5 7\n")},
+		"summa02.out":   {Data: []byte("12\n")},
+		"summa03.in":    {Data: []byte("0 0\n")},
+		"summa03.out":   {Data: []byte("0\n")},
+		"points.txt":    {Data: []byte("20 30 50\n")},
+		"summa.pdf":     {Data: []byte("%PDF-1.4\n")},
+		"parbaude.cpp":  {Data: []byte("int main() {}\n")},
+		"summa04.in":    {Data: []byte("1 1\n")},
+		"komentari.doc": {Data: []byte("?")},
+	}
+
+	task, report, err := fstaskparser.ImportLegacyLIOFS(fsys, "summa")
+	require.NoErrorf(t, err, "failed to import task: %v", err)
+
+	assert.Equal(t, 3, len(task.GetTestsSortedByID()))
+	require.Equal(t, []int{1, 2, 3}, task.GetTestGroupIDs())
+	assert.Equal(t, fstaskparser.TestGroupInfo{GroupID: 3, Points: 50, Public: false, TestIDs: []int{3}, Subtask: 3}, task.GetInfoOnTestGroup(3))
+	assert.Empty(t, task.GetExamples())
+	assert.Equal(t, []byte("int main() {}\n"), task.GetCheckerSource())
+
+	_, err = task.GetPDFStatement("lv")
+	require.NoErrorf(t, err, "failed to get pdf statement: %v", err)
+
+	assert.Contains(t, report.Guesses, fstaskparser.Guess{
+		Subject:    "groups",
+		Confidence: fstaskparser.ConfidenceMedium,
+		Detail:     "no letter suffixes, every test is its own group",
+	})
+	assert.Contains(t, report.Guesses, fstaskparser.Guess{
+		Subject:    "points",
+		Confidence: fstaskparser.ConfidenceMedium,
+		Detail:     "points of the groups in order from points.txt",
+	})
+	assert.Equal(t, []string{"test summa04 without input or answer", "file komentari.doc", "file summa04.in"}, report.Unmapped)
+	assert.Contains(t, report.String(), "- [low] limits: no time limit found, using 1 s")
+}
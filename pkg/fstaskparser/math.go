@@ -0,0 +1,119 @@
+package fstaskparser
+
+import (
+	"strings"
+)
+
+// mathSpan is a `$...$` or `$$...$$` formula in Markdown text.
+type mathSpan struct {
+	Start    int // byte offset of the opening delimiter
+	End      int // byte offset after the closing delimiter
+	Line     int // 1-based line of the opening delimiter
+	Display  bool
+	Unclosed bool // no closing delimiter was found
+	Content  string
+}
+
+// findMathSpans extracts math spans from Markdown, skipping code spans,
+// fenced code blocks and escaped dollars. Inline math does not cross a
+// blank line, display math may span several lines.
+func findMathSpans(md string) []mathSpan {
+	res := make([]mathSpan, 0)
+	inFence := false
+	lineStart := true
+
+	for i := 0; i < len(md); {
+		if lineStart {
+			lineStart = false
+			trimmed := strings.TrimLeft(md[i:], " ")
+			if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+				inFence = !inFence
+				i = nextLineStart(md, i)
+				lineStart = true
+				continue
+			}
+			if inFence {
+				i = nextLineStart(md, i)
+				lineStart = true
+				continue
+			}
+		}
+
+		switch md[i] {
+		case '\n':
+			lineStart = true
+			i++
+		case '\\':
+			i += 2
+		case '`':
+			n := countRun(md, i, '`')
+			closing := strings.Index(md[i+n:], strings.Repeat("`", n))
+			if closing < 0 {
+				i += n
+			} else {
+				i += n + closing + n
+			}
+		case '$':
+			delim := "$"
+			if strings.HasPrefix(md[i:], "$$") {
+				delim = "$$"
+			}
+			span := scanMathSpan(md, i, delim)
+			res = append(res, span)
+			if span.Unclosed {
+				i += len(delim)
+			} else {
+				i = span.End
+			}
+		default:
+			i++
+		}
+	}
+
+	return res
+}
+
+func scanMathSpan(md string, start int, delim string) mathSpan {
+	span := mathSpan{
+		Start:   start,
+		Line:    strings.Count(md[:start], "\n") + 1,
+		Display: delim == "$$",
+	}
+
+	for j := start + len(delim); j < len(md); j++ {
+		switch {
+		case md[j] == '\\':
+			j++
+		case !span.Display && strings.HasPrefix(md[j:], "\n\n"):
+			span.Unclosed = true
+			span.End = j
+			span.Content = md[start+len(delim) : j]
+			return span
+		case strings.HasPrefix(md[j:], delim):
+			span.End = j + len(delim)
+			span.Content = md[start+len(delim) : j]
+			return span
+		}
+	}
+
+	span.Unclosed = true
+	span.End = len(md)
+	span.Content = md[min(start+len(delim), len(md)):]
+	return span
+}
+
+func nextLineStart(s string, i int) int {
+	nl := strings.IndexByte(s[i:], '\n')
+	if nl < 0 {
+		return len(s)
+	}
+	return i + nl + 1
+}
+
+func countRun(s string, i int, c byte) int {
+	n := 0
+	for i+n < len(s) && s[i+n] == c {
+		n++
+	}
+	return n
+}
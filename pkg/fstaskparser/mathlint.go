@@ -0,0 +1,186 @@
+package fstaskparser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// MathProblem is an error in a `$...$` or `$$...$$` formula of a
+// Markdown statement or example note.
+type MathProblem struct {
+	Language string // empty for example notes
+	Section  string // "story", "input", "output", "notes", "scoring", an additional section or "examples/<name>"
+	Line     int    // 1-based line within the section
+	Formula  string // formula including its delimiters
+	Message  string
+}
+
+func (p MathProblem) String() string {
+	if p.Language == "" {
+		return fmt.Sprintf("%s:%d: %s in %s", p.Section, p.Line, p.Message, p.Formula)
+	}
+	return fmt.Sprintf("%s/%s:%d: %s in %s", p.Language, p.Section, p.Line, p.Message, p.Formula)
+}
+
+// supportedMathCommands is the subset of KaTeX commands statements may use.
+var supportedMathCommands = toSet(
+	// greek letters
+	"alpha", "beta", "gamma", "delta", "epsilon", "varepsilon", "zeta", "eta", "theta",
+	"vartheta", "iota", "kappa", "lambda", "mu", "nu", "xi", "pi", "varpi", "rho",
+	"varrho", "sigma", "varsigma", "tau", "upsilon", "phi", "varphi", "chi", "psi", "omega",
+	"Gamma", "Delta", "Theta", "Lambda", "Xi", "Pi", "Sigma", "Upsilon", "Phi", "Psi", "Omega",
+	// relations
+	"leq", "le", "geq", "ge", "neq", "ne", "lt", "gt", "approx", "equiv", "sim", "simeq",
+	"cong", "propto", "ll", "gg", "mid", "nmid", "parallel", "perp",
+	"in", "notin", "ni", "subset", "subseteq", "supset", "supseteq",
+	// operators
+	"times", "cdot", "div", "pm", "mp", "ast", "star", "circ", "bullet", "oplus", "otimes",
+	"cup", "cap", "setminus", "wedge", "vee", "land", "lor", "lnot", "neg",
+	"sum", "prod", "int", "bigcup", "bigcap", "bigoplus",
+	// functions
+	"min", "max", "log", "ln", "lg", "exp", "sin", "cos", "tan", "gcd", "lcm", "mod",
+	"bmod", "pmod", "lim", "sup", "inf", "det", "deg", "dim", "arg", "operatorname",
+	// arrows
+	"to", "gets", "rightarrow", "leftarrow", "leftrightarrow", "Rightarrow", "Leftarrow",
+	"Leftrightarrow", "iff", "implies", "mapsto", "uparrow", "downarrow",
+	// delimiters
+	"left", "right", "big", "Big", "bigg", "Bigg", "lfloor", "rfloor", "lceil", "rceil",
+	"langle", "rangle", "lbrace", "rbrace", "vert", "Vert",
+	// constructs
+	"frac", "dfrac", "tfrac", "sqrt", "binom", "dbinom", "overline", "underline", "hat",
+	"bar", "vec", "tilde", "dot", "ddot", "overbrace", "underbrace", "stackrel", "not",
+	"begin", "end",
+	// symbols
+	"infty", "emptyset", "varnothing", "forall", "exists", "partial", "nabla", "angle",
+	"triangle", "ldots", "cdots", "vdots", "ddots", "dots", "prime", "degree", "dagger",
+	"ell", "hbar", "aleph",
+	// text and fonts
+	"text", "textbf", "textit", "texttt", "mathrm", "mathbf", "mathit", "mathtt",
+	"mathbb", "mathcal", "mathsf", "boldsymbol", "displaystyle", "textstyle",
+	// spacing
+	"quad", "qquad", "space", "hspace", "phantom",
+)
+
+// supportedMathEnvironments are the environments allowed in \begin{...}
+var supportedMathEnvironments = toSet(
+	"matrix", "pmatrix", "bmatrix", "vmatrix", "cases", "aligned", "array", "gathered",
+)
+
+func toSet(items ...string) map[string]bool {
+	res := make(map[string]bool, len(items))
+	for _, item := range items {
+		res[item] = true
+	}
+	return res
+}
+
+var mathCommandRegexp = regexp.MustCompile(`\\([a-zA-Z]+|.)`)
+
+var mathEnvironmentRegexp = regexp.MustCompile(`^\\(begin|end)\s*\{([a-zA-Z*]*)\}`)
+
+// LintStatementMath checks the math formulas of every Markdown statement
+// section and example note: delimiters must be closed, braces balanced,
+// \left and \right paired and only supported KaTeX commands used.
+func (t *Task) LintStatementMath() []MathProblem {
+	res := make([]MathProblem, 0)
+	t.forEachMarkdownSection(func(lang string, section string, md string) {
+		for _, span := range findMathSpans(md) {
+			for _, p := range lintMathSpan(md, span) {
+				p.Language = lang
+				p.Section = section
+				res = append(res, p)
+			}
+		}
+	})
+	return res
+}
+
+func lintMathSpan(md string, span mathSpan) []MathProblem {
+	res := make([]MathProblem, 0)
+	formula := md[span.Start:span.End]
+	delim := "$"
+	if span.Display {
+		delim = "$$"
+	}
+	contentStart := span.Start + len(delim)
+	report := func(offset int, format string, args ...any) {
+		res = append(res, MathProblem{
+			Line:    strings.Count(md[:offset], "\n") + 1,
+			Formula: strings.TrimSpace(formula),
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	if span.Unclosed {
+		report(span.Start, "unclosed %s", delim)
+		return res
+	}
+	if strings.TrimSpace(span.Content) == "" {
+		report(span.Start, "empty formula")
+		return res
+	}
+
+	braces := make([]int, 0)
+	lefts := make([]int, 0)
+	envs := make([]string, 0)
+	content := span.Content
+	for i := 0; i < len(content); i++ {
+		switch content[i] {
+		case '{':
+			braces = append(braces, contentStart+i)
+		case '}':
+			if len(braces) == 0 {
+				report(contentStart+i, "unmatched }")
+				continue
+			}
+			braces = braces[:len(braces)-1]
+		case '\\':
+			m := mathCommandRegexp.FindStringSubmatch(content[i:])
+			if m == nil {
+				continue
+			}
+			name := m[1]
+			switch {
+			case len(name) == 1:
+				// escaped symbol such as \{ or \,
+			case !supportedMathCommands[name]:
+				report(contentStart+i, "unsupported command \\%s", name)
+			case name == "left":
+				lefts = append(lefts, contentStart+i)
+			case name == "right":
+				if len(lefts) == 0 {
+					report(contentStart+i, "\\right without \\left")
+				} else {
+					lefts = lefts[:len(lefts)-1]
+				}
+			case name == "begin" || name == "end":
+				env := mathEnvironmentRegexp.FindStringSubmatch(content[i:])
+				if env == nil {
+					report(contentStart+i, "\\%s without environment name", name)
+				} else if !supportedMathEnvironments[env[2]] {
+					report(contentStart+i, "unsupported environment %s", env[2])
+				} else if name == "begin" {
+					envs = append(envs, env[2])
+				} else if len(envs) == 0 || envs[len(envs)-1] != env[2] {
+					report(contentStart+i, "\\end{%s} without matching \\begin", env[2])
+				} else {
+					envs = envs[:len(envs)-1]
+				}
+			}
+			i += len(m[0]) - 1
+		}
+	}
+
+	for _, offset := range braces {
+		report(offset, "unclosed {")
+	}
+	for _, offset := range lefts {
+		report(offset, "\\left without \\right")
+	}
+	for _, env := range envs {
+		report(span.Start, "unclosed environment %s", env)
+	}
+
+	return res
+}
@@ -0,0 +1,55 @@
+package fstaskparser_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/programme-lv/fs-task-format-parser/pkg/fstaskparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLintingStatementMath(t *testing.T) {
+	parsedTask, err := fstaskparser.Read(testTaskPath)
+	require.NoErrorf(t, err, "failed to read task: %v", err)
+	assert.Empty(t, parsedTask.LintStatementMath())
+
+	tmpDirectory, err := os.MkdirTemp("", "fstaskparser-test-")
+	require.NoErrorf(t, err, "failed to create temporary directory: %v", err)
+	defer os.RemoveAll(tmpDirectory)
+
+	outputDirectory := filepath.Join(tmpDirectory, "kvadrputekl")
+	err = parsedTask.Store(outputDirectory)
+	require.NoErrorf(t, err, "failed to store task: %v", err)
+
+	inputPath := filepath.Join(outputDirectory, "statements", "md", "lv", "input.md")
+	input := "Pirmajā rindā dots $N$ un `$x` kods.\n\n" +
+		"Ierobežojumi: $\\frac{1}{2$ un $\\foo{N}$.\n\n" +
+		"$$\n\\left( \\sum_{i=1}^{N} a_i\n$$\n\n" +
+		"Cena \\$5 un nepabeigts $N \\leq 5\n\nbeigas."
+	err = os.WriteFile(inputPath, []byte(input), 0644)
+	require.NoErrorf(t, err, "failed to write input.md: %v", err)
+
+	storedTask, err := fstaskparser.Read(outputDirectory)
+	require.NoErrorf(t, err, "failed to read task: %v", err)
+
+	problems := storedTask.LintStatementMath()
+	messages := []string{}
+	for _, p := range problems {
+		assert.Equal(t, "lv", p.Language)
+		assert.Equal(t, "input", p.Section)
+		messages = append(messages, p.Message)
+	}
+	assert.Equal(t, []string{
+		"unclosed {",
+		"unsupported command \\foo",
+		"\\left without \\right",
+		"unclosed $",
+	}, messages)
+	assert.Equal(t, 3, problems[0].Line)
+	assert.Equal(t, "$\\frac{1}{2$", problems[0].Formula)
+	assert.Equal(t, 6, problems[2].Line)
+	assert.Equal(t, 9, problems[3].Line)
+	assert.Equal(t, "lv/input:3: unsupported command \\foo in $\\foo{N}$", problems[1].String())
+}
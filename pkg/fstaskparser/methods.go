@@ -2,8 +2,10 @@ package fstaskparser
 
 import (
 	"fmt"
+	"io/fs"
 	"log"
 	"sort"
+	"strings"
 )
 
 func (t *Task) GetCPUTimeLimitInSeconds() float64 {
@@ -100,6 +102,12 @@ func (t *Task) AddTest(input []byte, answer []byte) int {
 	return mex
 }
 
+// GetReadDiagnostics returns the non-fatal problems found by Read, such as
+// files in the tests directory that could not be paired.
+func (t *Task) GetReadDiagnostics() []ReadDiagnostic {
+	return t.readDiagnostics
+}
+
 func (t *Task) AssignFilenameToTest(filename string, testID int) {
 	_, ok1 := t.testIDToFilename[testID]
 	_, ok2 := t.testFilenameToID[filename]
@@ -255,6 +263,7 @@ func (t *Task) AddTestGroup(points int, public bool, testIDs []int, subtask int)
 }
 
 func (t *Task) GetPDFStatement(lang string) ([]byte, error) {
+	lang = lookupLanguageCode(lang)
 	statement, ok := t.pdfStatements[lang]
 	if !ok {
 		return nil, fmt.Errorf("pdf statement for language %s not found", lang)
@@ -281,6 +290,11 @@ func (t *Task) GetAllPDFStatements() []PDFStatement {
 }
 
 func (t *Task) AddPDFStatement(lang string, statement []byte) error {
+	lang, err := normalizeLanguageCode(lang)
+	if err != nil {
+		return err
+	}
+
 	_, ok := t.pdfStatements[lang]
 	if ok {
 		return fmt.Errorf("pdf statement for language %s already exists", lang)
@@ -290,6 +304,27 @@ func (t *Task) AddPDFStatement(lang string, statement []byte) error {
 	return nil
 }
 
+// ReplacePDFStatement sets the PDF statement of a language, replacing an existing one
+func (t *Task) ReplacePDFStatement(lang string, statement []byte) error {
+	lang, err := normalizeLanguageCode(lang)
+	if err != nil {
+		return err
+	}
+
+	t.pdfStatements[lang] = statement
+	return nil
+}
+
+func (t *Task) RemovePDFStatement(lang string) error {
+	lang = lookupLanguageCode(lang)
+	if _, ok := t.pdfStatements[lang]; !ok {
+		return fmt.Errorf("pdf statement for language %s not found", lang)
+	}
+
+	delete(t.pdfStatements, lang)
+	return nil
+}
+
 func (t *Task) AddVisibleInputSubtask(subtask int) error {
 	alreadyAdded := false
 
@@ -320,6 +355,7 @@ type MarkdownStatement struct {
 	Output   string
 	Notes    *string
 	Scoring  *string
+	Sections map[string]string // additional sections by name, e.g. "interaction"
 }
 
 func (t *Task) GetMarkdownStatements() []MarkdownStatement {
@@ -338,6 +374,60 @@ func (t *Task) SetMarkdownStatements(statements []MarkdownStatement) {
 	}
 }
 
+func (t *Task) findMarkdownStatement(lang string) int {
+	lang = lookupLanguageCode(lang)
+	for i, st := range t.mdStatements {
+		if st.Language != nil && *st.Language == lang {
+			return i
+		}
+	}
+	return -1
+}
+
+func (t *Task) GetMarkdownStatement(lang string) (MarkdownStatement, error) {
+	i := t.findMarkdownStatement(lang)
+	if i < 0 {
+		return MarkdownStatement{}, fmt.Errorf("markdown statement for language %s not found", lang)
+	}
+
+	return MarkdownStatement(t.mdStatements[i]), nil
+}
+
+// SetMarkdownStatement adds or replaces the Markdown statement of a language.
+// The language must be a BCP 47 tag, st.Language is ignored. Additional
+// section names must be valid and not one of the core sections.
+func (t *Task) SetMarkdownStatement(lang string, st MarkdownStatement) error {
+	lang, err := normalizeLanguageCode(lang)
+	if err != nil {
+		return err
+	}
+	for name := range st.Sections {
+		err = validateStatementSectionName(name)
+		if err != nil {
+			return err
+		}
+	}
+
+	st.Language = &lang
+	if i := t.findMarkdownStatement(lang); i >= 0 {
+		t.mdStatements[i] = mDStatement(st)
+	} else {
+		t.mdStatements = append(t.mdStatements, mDStatement(st))
+	}
+	return nil
+}
+
+// RemoveStatement removes the Markdown statement of a language
+func (t *Task) RemoveStatement(lang string) error {
+	i := t.findMarkdownStatement(lang)
+	if i < 0 {
+		return fmt.Errorf("markdown statement for language %s not found", lang)
+	}
+
+	t.mdStatements = append(t.mdStatements[:i], t.mdStatements[i+1:]...)
+	return nil
+}
+
 type Asset struct {
 	RelativePath string // relative path from assets directory
 	Content      []byte
@@ -360,6 +450,43 @@ func (t *Task) GetAssets() []asset {
 	return t.assets
 }
 
+// validateRelativePath rejects paths of assets and test files that are
+// absolute, use backslashes or leave their directory with ".."
+func validateRelativePath(relativePath string) error {
+	if strings.Contains(relativePath, `\`) || relativePath == "." || !fs.ValidPath(relativePath) {
+		return fmt.Errorf("invalid relative path %q", relativePath)
+	}
+	return nil
+}
+
+// AddAsset adds a file to the assets directory, relativePath uses forward slashes
+func (t *Task) AddAsset(relativePath string, content []byte) error {
+	err := validateRelativePath(relativePath)
+	if err != nil {
+		return fmt.Errorf("asset: %w", err)
+	}
+	for _, a := range t.assets {
+		if a.RelativePath == relativePath {
+			return fmt.Errorf("asset %s already exists", relativePath)
+		}
+	}
+
+	t.assets = append(t.assets, asset{
+		RelativePath: relativePath,
+		Content:      content,
+	})
+	return nil
+}
+
 func (t *Task) GetOriginNotes() map[string]string {
 	return t.OriginNotes
 }
+
+// GetCheckerSource returns the C++ source of the checker, nil if there is none
+func (t *Task) GetCheckerSource() []byte {
+	return t.checker
+}
+
+func (t *Task) SetCheckerSource(source []byte) {
+	t.checker = source
+}
@@ -0,0 +1,487 @@
+package fstaskparser
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// polygonLanguages maps Polygon statement language names to BCP 47 tags
+var polygonLanguages = map[string]string{
+	"english":    "en",
+	"russian":    "ru",
+	"latvian":    "lv",
+	"lithuanian": "lt",
+	"estonian":   "et",
+	"ukrainian":  "uk",
+	"polish":     "pl",
+	"german":     "de",
+	"french":     "fr",
+	"spanish":    "es",
+	"italian":    "it",
+	"portuguese": "pt",
+	"romanian":   "ro",
+	"bulgarian":  "bg",
+	"hungarian":  "hu",
+	"georgian":   "ka",
+	"armenian":   "hy",
+	"kazakh":     "kk",
+	"chinese":    "zh",
+	"japanese":   "ja",
+	"korean":     "ko",
+	"vietnamese": "vi",
+	"turkish":    "tr",
+	"persian":    "fa",
+	"arabic":     "ar",
+}
+
+type polygonProblemXML struct {
+	ShortName string `xml:"short-name,attr"`
+	Names     []struct {
+		Language string `xml:"language,attr"`
+		Value    string `xml:"value,attr"`
+	} `xml:"names>name"`
+	Statements []struct {
+		Language string `xml:"language,attr"`
+		Path     string `xml:"path,attr"`
+		Type     string `xml:"type,attr"`
+	} `xml:"statements>statement"`
+	Tutorials []struct {
+		Language string `xml:"language,attr"`
+		Path     string `xml:"path,attr"`
+		Type     string `xml:"type,attr"`
+	} `xml:"tutorials>tutorial"`
+	Judging struct {
+		InputFile  string           `xml:"input-file,attr"`
+		OutputFile string           `xml:"output-file,attr"`
+		RunCount   int              `xml:"run-count,attr"`
+		Testsets   []polygonTestset `xml:"testset"`
+	} `xml:"judging"`
+	Assets struct {
+		Checker *struct {
+			Name   string `xml:"name,attr"`
+			Source struct {
+				Path string `xml:"path,attr"`
+			} `xml:"source"`
+		} `xml:"checker"`
+		Interactor *struct {
+			Source struct {
+				Path string `xml:"path,attr"`
+			} `xml:"source"`
+		} `xml:"interactor"`
+		Validators []struct{} `xml:"validators>validator"`
+		Solutions  []struct {
+			Tag string `xml:"tag,attr"`
+		} `xml:"solutions>solution"`
+	} `xml:"assets"`
+	Tags []struct {
+		Value string `xml:"value,attr"`
+	} `xml:"tags>tag"`
+}
+
+type polygonTestset struct {
+	Name              string `xml:"name,attr"`
+	TimeLimitMillis   int    `xml:"time-limit"`
+	MemoryLimitBytes  int64  `xml:"memory-limit"`
+	TestCount         int    `xml:"test-count"`
+	InputPathPattern  string `xml:"input-path-pattern"`
+	AnswerPathPattern string `xml:"answer-path-pattern"`
+	Tests             []struct {
+		Sample bool    `xml:"sample,attr"`
+		Group  string  `xml:"group,attr"`
+		Points float64 `xml:"points,attr"`
+	} `xml:"tests>test"`
+	Groups []struct {
+		Name           string  `xml:"name,attr"`
+		Points         float64 `xml:"points,attr"`
+		PointsPolicy   string  `xml:"points-policy,attr"`
+		FeedbackPolicy string  `xml:"feedback-policy,attr"`
+		Dependencies   []struct {
+			Group string `xml:"group,attr"`
+		} `xml:"dependencies>dependency"`
+	} `xml:"groups>group"`
+}
+
+// polygonSections maps statement-sections/<lang>/ files to Markdown statement parts
+var polygonSections = map[string]string{
+	"legend.tex":      "story",
+	"input.tex":       "input",
+	"output.tex":      "output",
+	"notes.tex":       "notes",
+	"scoring.tex":     "scoring",
+	"interaction.tex": "interaction",
+	"tutorial.tex":    "editorial",
+}
+
+// polygonIgnoredFiles in statement-sections/<lang>/ carry nothing the task needs
+var polygonIgnoredFiles = map[string]bool{
+	"name.tex":                true,
+	"problem-properties.json": true,
+}
+
+// ImportPolygon reads a Polygon full package, either unpacked or as a zip
+// archive, into a Task. Parts of the package without a counterpart in the
+// task are listed in the returned report.
+func ImportPolygon(packagePath string) (*Task, *ImportReport, error) {
+	fsys, closePkg, err := openPackage(packagePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closePkg()
+
+	return ImportPolygonFS(fsys)
+}
+
+// ImportPolygonFS reads a Polygon full package rooted at fsys into a Task.
+func ImportPolygonFS(fsys fs.FS) (*Task, *ImportReport, error) {
+	report := &ImportReport{Format: "polygon"}
+
+	content, err := fs.ReadFile(fsys, "problem.xml")
+	if err != nil {
+		log.Printf("Error reading problem.xml: %v\n", err)
+		return nil, nil, fmt.Errorf("error reading problem.xml: %w", err)
+	}
+
+	var p polygonProblemXML
+	err = xml.Unmarshal(content, &p)
+	if err != nil {
+		log.Printf("Error parsing problem.xml: %v\n", err)
+		return nil, nil, fmt.Errorf("error parsing problem.xml: %w", err)
+	}
+
+	name := p.ShortName
+	if len(p.Names) > 0 {
+		name = p.Names[0].Value
+		for _, n := range p.Names[1:] {
+			report.unmapped("problem name %q in %s", n.Value, n.Language)
+		}
+	}
+	t, err := NewTask(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, tag := range p.Tags {
+		t.problemTags = append(t.problemTags, tag.Value)
+	}
+
+	if p.Judging.InputFile != "" || p.Judging.OutputFile != "" {
+		report.unmapped("file input/output (%q, %q), tests use standard input and output", p.Judging.InputFile, p.Judging.OutputFile)
+	}
+	if p.Judging.RunCount > 1 {
+		report.unmapped("run count %d", p.Judging.RunCount)
+	}
+
+	var testset *polygonTestset
+	for i := range p.Judging.Testsets {
+		if p.Judging.Testsets[i].Name == "tests" && testset == nil {
+			testset = &p.Judging.Testsets[i]
+		} else {
+			report.unmapped("testset %q", p.Judging.Testsets[i].Name)
+		}
+	}
+	if testset == nil {
+		return nil, nil, fmt.Errorf("problem.xml has no \"tests\" testset")
+	}
+
+	err = t.importPolygonTestset(fsys, testset, report)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = t.importPolygonStatements(fsys, &p, report)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if c := p.Assets.Checker; c != nil {
+		source, err := fs.ReadFile(fsys, c.Source.Path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error reading checker: %w", err)
+		}
+		t.checker = source
+		if strings.Contains(string(source), "testlib.h") {
+			report.unmapped("checker %s includes testlib.h, which is not stored with the task", c.Name)
+		}
+	}
+	if p.Assets.Interactor != nil {
+		report.unmapped("interactor %s", p.Assets.Interactor.Source.Path)
+	}
+	if len(p.Assets.Validators) > 0 {
+		report.unmapped("%d validators", len(p.Assets.Validators))
+	}
+	if len(p.Assets.Solutions) > 0 {
+		report.unmapped("%d solutions", len(p.Assets.Solutions))
+	}
+
+	return t, report, nil
+}
+
+func (t *Task) importPolygonTestset(fsys fs.FS, ts *polygonTestset, report *ImportReport) error {
+	t.cpuTimeSeconds = float64(ts.TimeLimitMillis) / 1000
+	t.memoryMegabytes = int(ts.MemoryLimitBytes / (1024 * 1024))
+	if ts.MemoryLimitBytes%(1024*1024) != 0 {
+		report.unmapped("memory limit of %d bytes rounded down to %d MB", ts.MemoryLimitBytes, t.memoryMegabytes)
+	}
+
+	if ts.TestCount != len(ts.Tests) {
+		return fmt.Errorf("testset declares %d tests but lists %d", ts.TestCount, len(ts.Tests))
+	}
+
+	groupTestIDs := make(map[string][]int)
+	groupTestPoints := make(map[string]float64)
+	groupAllSamples := make(map[string]bool)
+	for i, test := range ts.Tests {
+		n := i + 1
+		input, err := fs.ReadFile(fsys, fmt.Sprintf(ts.InputPathPattern, n))
+		if err != nil {
+			return fmt.Errorf("error reading test %d input, generated tests must be included in the package: %w", n, err)
+		}
+		answer, err := fs.ReadFile(fsys, fmt.Sprintf(ts.AnswerPathPattern, n))
+		if err != nil {
+			return fmt.Errorf("error reading test %d answer: %w", n, err)
+		}
+
+		id := t.AddTest(input, answer)
+		if test.Sample {
+			t.AddExample(input, answer, nil)
+		}
+
+		if test.Group == "" {
+			if test.Points != 0 {
+				report.unmapped("%g points of test %d outside of groups", test.Points, n)
+			}
+			continue
+		}
+		if _, ok := groupAllSamples[test.Group]; !ok {
+			groupAllSamples[test.Group] = true
+		}
+		groupAllSamples[test.Group] = groupAllSamples[test.Group] && test.Sample
+		groupTestIDs[test.Group] = append(groupTestIDs[test.Group], id)
+		groupTestPoints[test.Group] += test.Points
+	}
+
+	declared := make(map[string]bool)
+	for i, g := range ts.Groups {
+		declared[g.Name] = true
+		subtask, err := strconv.Atoi(g.Name)
+		if err != nil {
+			subtask = i + 1
+			report.unmapped("group %q numbered as subtask %d", g.Name, subtask)
+		}
+
+		points := g.Points
+		if g.PointsPolicy == "each-test" {
+			points = groupTestPoints[g.Name]
+			if points != 0 {
+				report.unmapped("group %q awards points per test, imported as %g points for the whole group", g.Name, points)
+			}
+		}
+		if points != float64(int(points)) {
+			report.unmapped("group %q fractional points %g rounded down", g.Name, points)
+		}
+
+		if len(g.Dependencies) > 0 {
+			deps := make([]string, 0, len(g.Dependencies))
+			for _, d := range g.Dependencies {
+				deps = append(deps, d.Group)
+			}
+			report.unmapped("group %q depends on groups %s", g.Name, strings.Join(deps, ", "))
+		}
+		if g.FeedbackPolicy != "" && g.FeedbackPolicy != "complete" && g.FeedbackPolicy != "points" {
+			report.unmapped("group %q feedback policy %q", g.Name, g.FeedbackPolicy)
+		}
+
+		err = t.AddTestGroupWithID(i+1, int(points), groupAllSamples[g.Name], groupTestIDs[g.Name], subtask)
+		if err != nil {
+			return err
+		}
+	}
+
+	undeclared := make([]string, 0)
+	for name := range groupTestIDs {
+		if !declared[name] {
+			undeclared = append(undeclared, name)
+		}
+	}
+	sort.Strings(undeclared)
+	for _, name := range undeclared {
+		report.unmapped("tests of undeclared group %q", name)
+	}
+
+	return nil
+}
+
+func (t *Task) importPolygonStatements(fsys fs.FS, p *polygonProblemXML, report *ImportReport) error {
+	for _, st := range p.Statements {
+		lang, ok := polygonLanguages[st.Language]
+		if !ok {
+			report.unmapped("statement in unknown language %q", st.Language)
+			continue
+		}
+		if st.Type == "application/pdf" {
+			pdf, err := fs.ReadFile(fsys, st.Path)
+			if err != nil {
+				return fmt.Errorf("error reading pdf statement: %w", err)
+			}
+			err = t.ReplacePDFStatement(lang, pdf)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, tu := range p.Tutorials {
+		lang, ok := polygonLanguages[tu.Language]
+		if !ok || tu.Type != "application/pdf" {
+			continue
+		}
+		pdf, err := fs.ReadFile(fsys, tu.Path)
+		if err != nil {
+			return fmt.Errorf("error reading pdf tutorial: %w", err)
+		}
+		err = t.SetEditorial(lang, Editorial{PDF: pdf})
+		if err != nil {
+			return err
+		}
+	}
+
+	langDirs, err := fs.ReadDir(fsys, "statement-sections")
+	if err != nil {
+		report.unmapped("no statement-sections directory, Markdown statements are not imported")
+		return nil
+	}
+
+	for _, langDir := range langDirs {
+		if !langDir.IsDir() {
+			continue
+		}
+		lang, ok := polygonLanguages[langDir.Name()]
+		if !ok {
+			report.unmapped("statement sections in unknown language %q", langDir.Name())
+			continue
+		}
+		dirPath := path.Join("statement-sections", langDir.Name())
+		files, err := fs.ReadDir(fsys, dirPath)
+		if err != nil {
+			return fmt.Errorf("error reading statement sections: %w", err)
+		}
+
+		st := MarkdownStatement{}
+		var tutorial *string
+		images := make(map[string]bool)
+		tutorialImages := make(map[string]bool)
+		for _, f := range files {
+			if f.IsDir() || polygonIgnoredFiles[f.Name()] || strings.HasPrefix(f.Name(), "example.") {
+				continue
+			}
+			section, ok := polygonSections[f.Name()]
+			if !ok {
+				if !strings.HasSuffix(f.Name(), ".tex") {
+					continue // images are imported when referenced
+				}
+				report.unmapped("statement section %s/%s", langDir.Name(), f.Name())
+				continue
+			}
+
+			tex, err := fs.ReadFile(fsys, path.Join(dirPath, f.Name()))
+			if err != nil {
+				return fmt.Errorf("error reading statement section: %w", err)
+			}
+			md, refs, unsupported := texToMarkdown(string(tex))
+			for _, u := range unsupported {
+				report.unmapped("%s in %s/%s kept as LaTeX", u, langDir.Name(), f.Name())
+			}
+			for _, ref := range refs {
+				if section == "editorial" {
+					tutorialImages[ref] = true
+				} else {
+					images[ref] = true
+				}
+			}
+
+			switch section {
+			case "story":
+				st.Story = md
+			case "input":
+				st.Input = md
+			case "output":
+				st.Output = md
+			case "notes":
+				st.Notes = &md
+			case "scoring":
+				st.Scoring = &md
+			case "editorial":
+				tutorial = &md
+			default:
+				if st.Sections == nil {
+					st.Sections = map[string]string{}
+				}
+				st.Sections[section] = md
+			}
+		}
+
+		t.assets = readPolygonImages(fsys, dirPath, images, t.assets, report)
+
+		if tutorial != nil {
+			e, _ := t.GetEditorial(lang)
+			e.Markdown = tutorial
+			for _, a := range readPolygonImages(fsys, dirPath, tutorialImages, nil, report) {
+				e.Assets = append(e.Assets, Asset(a))
+			}
+			err = t.SetEditorial(lang, e)
+			if err != nil {
+				return err
+			}
+		}
+
+		if st.Story == "" && st.Input == "" && st.Output == "" {
+			continue
+		}
+		for _, part := range []struct{ name, md string }{{"legend", st.Story}, {"input", st.Input}, {"output", st.Output}} {
+			if strings.TrimSpace(part.md) == "" {
+				report.unmapped("statement in %s has no %s section", langDir.Name(), part.name)
+			}
+		}
+		err = t.SetMarkdownStatement(lang, st)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readPolygonImages appends the referenced images of a statement sections
+// directory to assets, skipping the ones already there.
+func readPolygonImages(fsys fs.FS, dirPath string, refs map[string]bool, assets []asset, report *ImportReport) []asset {
+	existing := make(map[string]bool, len(assets))
+	for _, a := range assets {
+		existing[a.RelativePath] = true
+	}
+
+	names := make([]string, 0, len(refs))
+	for ref := range refs {
+		names = append(names, ref)
+	}
+	sort.Strings(names)
+
+	for _, ref := range names {
+		if existing[ref] {
+			continue
+		}
+		img, err := fs.ReadFile(fsys, path.Join(dirPath, ref))
+		if err != nil {
+			report.unmapped("image %s/%s not found", dirPath, ref)
+			continue
+		}
+		assets = append(assets, asset{RelativePath: ref, Content: img})
+		existing[ref] = true
+	}
+	return assets
+}
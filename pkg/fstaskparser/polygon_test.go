@@ -0,0 +1,86 @@
+package fstaskparser_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/programme-lv/fs-task-format-parser/pkg/fstaskparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var polygonPackagePath = filepath.Join(prjRootPath, "testdata", "polygon-aplusb")
+
+func TestImportingPolygonPackage(t *testing.T) {
+	task, report, err := fstaskparser.ImportPolygon(polygonPackagePath)
+	require.NoErrorf(t, err, "failed to import package: %v", err)
+
+	assert.Equal(t, "A plus B", task.GetTaskName())
+	assert.Equal(t, 1.5, task.GetCPUTimeLimitInSeconds())
+	assert.Equal(t, 256, task.GetMemoryLimitInMegabytes())
+	assert.Equal(t, []string{"implementation", "math"}, task.GetProblemTags())
+
+	tests := task.GetTestsSortedByID()
+	require.Equal(t, 5, len(tests))
+	assert.Equal(t, []byte("1000000000 1000000000\n"), tests[3].Input)
+	assert.Equal(t, []byte("2000000000\n"), tests[3].Answer)
+
+	examples := task.GetExamples()
+	require.Equal(t, 1, len(examples))
+	assert.Equal(t, []byte("1 2\n"), examples[0].Input)
+	assert.Equal(t, []byte("3\n"), examples[0].Output)
+
+	require.Equal(t, []int{1, 2, 3}, task.GetTestGroupIDs())
+	assert.Equal(t, fstaskparser.TestGroupInfo{GroupID: 1, Points: 0, Public: true, TestIDs: []int{1}, Subtask: 0}, task.GetInfoOnTestGroup(1))
+	assert.Equal(t, fstaskparser.TestGroupInfo{GroupID: 2, Points: 40, Public: false, TestIDs: []int{2, 3}, Subtask: 1}, task.GetInfoOnTestGroup(2))
+	assert.Equal(t, fstaskparser.TestGroupInfo{GroupID: 3, Points: 60, Public: false, TestIDs: []int{4, 5}, Subtask: 2}, task.GetInfoOnTestGroup(3))
+
+	st, err := task.GetMarkdownStatement("en")
+	require.NoErrorf(t, err, "failed to get statement: %v", err)
+	assert.Equal(t, "Given two integers $a$ and $b$, compute their sum — the **only** number you need to print.\n\n![](sum.png)\n", st.Story)
+	assert.Equal(t, "The only line contains two integers $a$ and $b$ ($-10^9 \\le a, b \\le 10^9$).\n", st.Input)
+	// ligatures are converted in text but not in code
+	assert.Equal(t, "Print `a+b` – for example with `cout << a+b; i--`.\n", st.Output)
+	require.NotNil(t, st.Scoring)
+	assert.Equal(t, "- Group 1 (40 points): $0 \\le a, b \\le 100$;\n- Group 2 (60 points): no additional constraints, the tests are\n\n  1. small,\n  1. large.\n", *st.Scoring)
+	assert.Nil(t, st.Notes)
+	assert.Empty(t, task.FindMissingAssetReferences())
+
+	pdf, err := task.GetPDFStatement("en")
+	require.NoErrorf(t, err, "failed to get pdf statement: %v", err)
+	assert.Equal(t, []byte("%PDF-1.4\n% A plus B\n"), pdf)
+
+	editorial, err := task.GetEditorial("en")
+	require.NoErrorf(t, err, "failed to get editorial: %v", err)
+	assert.Contains(t, *editorial.Markdown, "does not fit into `int`.")
+	assert.Contains(t, *editorial.Markdown, "\\begin{tabular}{cc} a & b \\end{tabular}")
+
+	assert.Contains(t, string(task.GetCheckerSource()), "registerTestlibCmd")
+
+	assert.Equal(t, []string{
+		"problem name \"A плюс B\" in russian",
+		"group \"1\" depends on groups 0",
+		"group \"1\" feedback policy \"icpc\"",
+		"group \"2\" depends on groups 0, 1",
+		"environment tabular in english/tutorial.tex kept as LaTeX",
+		"checker std::ncmp.cpp includes testlib.h, which is not stored with the task",
+		"1 validators",
+		"1 solutions",
+	}, report.Unmapped)
+
+	tmpDirectory, err := os.MkdirTemp("", "fstaskparser-test-")
+	require.NoErrorf(t, err, "failed to create temporary directory: %v", err)
+	defer os.RemoveAll(tmpDirectory)
+
+	outputDirectory := filepath.Join(tmpDirectory, "aplusb")
+	err = task.Store(outputDirectory)
+	require.NoErrorf(t, err, "failed to store task: %v", err)
+
+	storedTask, err := fstaskparser.Read(outputDirectory)
+	require.NoErrorf(t, err, "failed to read task: %v", err)
+	assert.Equal(t, task.GetCheckerSource(), storedTask.GetCheckerSource())
+	assert.Equal(t, task.GetTestsSortedByID(), storedTask.GetTestsSortedByID())
+	assert.Equal(t, task.GetMarkdownStatements(), storedTask.GetMarkdownStatements())
+	assert.Equal(t, task.GetAllEditorials(), storedTask.GetAllEditorials())
+}
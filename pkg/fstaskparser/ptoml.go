@@ -16,6 +16,17 @@ type ProblemTOML struct {
 	IllustrationImgFname string           `toml:"illustration_image,omitempty"`
 	VisInpSTs            []int            `toml:"visible_input_subtasks"`
 	TestIDOverwrite      map[string]int   `toml:"test_id_overwrite,omitempty"`
+
+	// StatementSections declares the visibility, "contestants" or "staff",
+	// of additional statement sections by name
+	StatementSections map[string]string `toml:"statement_sections,omitempty"`
+
+	Editorial *PTomlEditorial `toml:"editorial,omitempty"`
+}
+
+type PTomlEditorial struct {
+	// Release is "after_contest" or "immediately"
+	Release string `toml:"release"`
 }
 
 type PTomlMetadata struct {
@@ -62,6 +73,15 @@ func (task *Task) encodeProblemTOML() ([]byte, error) {
 		VisInpSTs:            task.visibleInputSubtasks,
 		TestIDOverwrite:      testIDOverwrite,
 	}
+	if len(task.statementSectionVisibility) > 0 {
+		t.StatementSections = make(map[string]string, len(task.statementSectionVisibility))
+		for name, v := range task.statementSectionVisibility {
+			t.StatementSections[name] = string(v)
+		}
+	}
+	if len(task.editorials) > 0 || task.editorialRelease != EditorialReleaseAfterContest {
+		t.Editorial = &PTomlEditorial{Release: string(task.editorialRelease)}
+	}
 	t.Specification = proglvFSTaskFormatSpecVersOfScript
 
 	// fill test groups
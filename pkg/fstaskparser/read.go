@@ -2,6 +2,7 @@ package fstaskparser
 
 import (
 	"fmt"
+	"io/fs"
 	"log"
 	"os"
 	"path/filepath"
@@ -14,7 +15,31 @@ func init() {
 	log.SetFlags(log.LstdFlags | log.Lshortfile)
 }
 
+// ReadOptions control how a task directory is read.
+type ReadOptions struct {
+	// Strict rejects problem.toml files containing keys that are not
+	// part of the specification, e.g. misspelled "memory_megabyte".
+	// The returned error is an *UnknownKeysError.
+	Strict bool
+
+	// TestFilePatterns recognise input and answer files in the tests
+	// directory, DefaultTestFilePatterns are used if empty.
+	TestFilePatterns []TestFilePattern
+
+	// TestDirGrouping creates test groups from nested test directories
+	// when problem.toml defines no test groups.
+	TestDirGrouping TestDirGrouping
+
+	// RequiredLanguages rejects tasks where one of these languages has no
+	// statement or lacks something another language has, see LanguageCoverage.
+	RequiredLanguages []string
+}
+
 func Read(taskRootDirPath string) (*Task, error) {
+	return ReadWithOptions(taskRootDirPath, ReadOptions{})
+}
+
+func ReadWithOptions(taskRootDirPath string, opts ReadOptions) (*Task, error) {
 	log.Printf("Starting to read directory: %s\n", taskRootDirPath)
 
 	t := Task{
@@ -34,6 +59,7 @@ func Read(taskRootDirPath string) (*Task, error) {
 		testFilenameToID:     map[string]int{},
 		testIDOverwrite:      map[string]int{},
 		testIDToFilename:     map[int]string{},
+		testFilePatterns:     map[string]TestFilePattern{},
 		tests:                []test{},
 		testGroupIDs:         []int{},
 		isTGroupPublic:       map[int]bool{},
@@ -44,6 +70,10 @@ func Read(taskRootDirPath string) (*Task, error) {
 		illstrImgFname:       "",
 		assets:               []asset{},
 		OriginNotes:          map[string]string{},
+
+		statementSectionVisibility: map[string]SectionVisibility{},
+		editorials:                 map[string]editorial{},
+		editorialRelease:           EditorialReleaseAfterContest,
 	}
 
 	problemTomlPath := filepath.Join(taskRootDirPath, "problem.toml")
@@ -57,6 +87,7 @@ func Read(taskRootDirPath string) (*Task, error) {
 	t.problemTomlContent = problemTomlContent
 	log.Println("problem.toml content read successfully")
 
+	// the specification version selects the schema for decoding the whole file
 	var specVersStruct struct {
 		Specification string `toml:"specification"`
 	}
@@ -67,22 +98,20 @@ func Read(taskRootDirPath string) (*Task, error) {
 		return nil, fmt.Errorf("failed to unmarshal the specification: %w", err)
 	}
 
-	specVers := specVersStruct.Specification
-	if len(specVers) == 0 {
+	if len(specVersStruct.Specification) == 0 {
 		log.Println("Empty specification found")
 		return nil, fmt.Errorf("empty specification")
 	}
-	if specVers[0] == 'v' {
-		specVers = specVers[1:]
+
+	specVers, err := ParseSpecVersion(specVersStruct.Specification)
+	if err != nil {
+		log.Printf("Error parsing specification version: %v\n", err)
+		return nil, fmt.Errorf("error parsing specification version: %w", err)
 	}
 
 	log.Printf("Specification version: %s\n", specVers)
 
-	semVersCmpRes, err := getCmpSemVersionsResult(specVers, proglvFSTaskFormatSpecVersOfScript)
-	if err != nil {
-		log.Printf("Error comparing sem versions: %v\n", err)
-		return nil, fmt.Errorf("error comparing sem versions: %w", err)
-	}
+	semVersCmpRes := specVers.Compare(proglvFSTaskFormatSpecVersion)
 
 	if semVersCmpRes > 0 {
 		log.Printf("Unsupported specification version (too new): %s\n", specVers)
@@ -93,67 +122,47 @@ func Read(taskRootDirPath string) (*Task, error) {
 		log.Printf("Warning: outdated specification version (too old): %s\n", specVers)
 	}
 
-	t.taskName, err = readTaskName(specVers, string(problemTomlContent))
+	log.Println("Decoding problem.toml")
+	pToml, err := decodeProblemToml(specVers, problemTomlContent, opts.Strict)
 	if err != nil {
-		log.Printf("Error reading task name: %v\n", err)
-		return nil, fmt.Errorf("error reading task name: %w", err)
+		log.Printf("Error decoding problem.toml: %v\n", err)
+		return nil, fmt.Errorf("error decoding problem.toml: %w", err)
 	}
 
-	t.cpuTimeSeconds, err = readCPUTimeLimitInSeconds(specVers, string(problemTomlContent))
+	err = t.applyProblemTOML(pToml)
 	if err != nil {
-		log.Printf("Error reading CPU time limit: %v\n", err)
-		return nil, fmt.Errorf("error reading cpu time limit: %w", err)
+		log.Printf("Error applying problem.toml: %v\n", err)
+		return nil, fmt.Errorf("error applying problem.toml: %w", err)
 	}
 
-	t.memoryMegabytes, err = readMemoryLimitInMegabytes(specVers, string(problemTomlContent))
-	if err != nil {
-		log.Printf("Error reading memory limit: %v\n", err)
-		return nil, fmt.Errorf("error reading memory limit: %w", err)
+	testFilePatterns := opts.TestFilePatterns
+	if len(testFilePatterns) == 0 {
+		testFilePatterns = DefaultTestFilePatterns
 	}
-
-	t.problemTags, err = readProblemTags(specVers, string(problemTomlContent))
-	if err != nil {
-		log.Printf("Error reading problem tags: %v\n", err)
-		return nil, fmt.Errorf("error reading problem tags: %w", err)
-	}
-
-	t.problemAuthors, err = readProblemAuthors(specVers, string(problemTomlContent))
-	if err != nil {
-		log.Printf("Error reading problem authors: %v\n", err)
-		return nil, fmt.Errorf("error reading problem authors: %w", err)
-	}
-
-	t.originOlympiad, err = readOriginOlympiad(specVers, string(problemTomlContent))
-	if err != nil {
-		log.Printf("Error reading origin olympiad: %v\n", err)
-		return nil, fmt.Errorf("error reading origin olympiad: %w", err)
-	}
-
-	t.difficultyOneToFive, err = readDifficultyOneToFive(specVers, string(problemTomlContent))
+	err = validateTestFilePatterns(testFilePatterns)
 	if err != nil {
-		log.Printf("Error reading difficulty: %v\n", err)
-		return nil, fmt.Errorf("error reading difficulty: %w", err)
+		log.Printf("Invalid test file patterns: %v\n", err)
+		return nil, fmt.Errorf("invalid test file patterns: %w", err)
 	}
 
 	log.Println("Reading test filenames from the tests directory")
-	t.testFnamesSorted, err = readTestFNamesSorted(filepath.Join(taskRootDirPath, "tests"))
+	testFiles, diagnostics, err := scanTestsDir(filepath.Join(taskRootDirPath, "tests"), testFilePatterns)
 	if err != nil {
 		log.Printf("Error reading test filenames: %v\n", err)
 		return nil, fmt.Errorf("error reading test filenames: %w", err)
 	}
+	t.readDiagnostics = append(t.readDiagnostics, diagnostics...)
+
+	for _, f := range testFiles {
+		t.testFnamesSorted = append(t.testFnamesSorted, f.Fname)
+		t.testFilePatterns[f.Fname] = f.FilePattern
+	}
 
 	for i, fname := range t.testFnamesSorted {
 		t.testFilenameToID[fname] = i + 1
 		t.testIDToFilename[i+1] = fname
 	}
 
-	log.Println("Reading test ID overwrite")
-	t.testIDOverwrite, err = readTestIDOverwrite(specVers, problemTomlContent)
-	if err != nil {
-		log.Printf("Error reading test ID overwrite: %v\n", err)
-		return nil, fmt.Errorf("error reading test id overwrite: %w", err)
-	}
-
 	for k, v := range t.testIDOverwrite {
 		t.testIDToFilename[v] = k
 		t.testFilenameToID[k] = v
@@ -178,7 +187,7 @@ func Read(taskRootDirPath string) (*Task, error) {
 	}
 
 	log.Println("Reading tests directory")
-	t.tests, err = readTestsDir(taskRootDirPath, t.testFilenameToID)
+	t.tests, err = readTestsDir(taskRootDirPath, testFiles, t.testFilenameToID)
 	if err != nil {
 		log.Printf("Error reading tests directory: %v\n", err)
 		return nil, fmt.Errorf("error reading tests directory: %w", err)
@@ -191,51 +200,29 @@ func Read(taskRootDirPath string) (*Task, error) {
 		return nil, fmt.Errorf("error reading examples directory: %w", err)
 	}
 
-	log.Println("Reading test group IDs")
-	t.testGroupIDs, err = readTestGroupIDs(specVers, problemTomlContent)
-	if err != nil {
-		log.Printf("Error reading test group IDs: %v\n", err)
-		return nil, fmt.Errorf("error reading test group IDs: %w", err)
-	}
-
-	log.Println("Reading is test group public")
-	t.isTGroupPublic, err = readIsTGroupPublic(specVers, problemTomlContent, t.testGroupIDs)
-	if err != nil {
-		log.Printf("Error reading is test group public: %v\n", err)
-		return nil, fmt.Errorf("error reading is test group public: %w", err)
-	}
-
-	log.Println("Reading test group points")
-	t.tGroupPoints, err = readTGroupPoints(specVers, problemTomlContent, t.testGroupIDs)
-	if err != nil {
-		log.Printf("Error reading test group points: %v\n", err)
-		return nil, fmt.Errorf("error reading test group points: %w", err)
-	}
-
-	log.Println("Reading test group to subtask map")
-	t.tGroupToStMap, err = readTGroupToStMap(specVers, problemTomlContent)
-	if err != nil {
-		log.Printf("Error reading test group to subtask map: %v\n", err)
-		return nil, fmt.Errorf("error reading test group to subtask map: %w", err)
-	}
-
-	log.Println("Reading test group test IDs")
-	t.tGroupTestIDs, err = readTGroupTestIDs(specVers, problemTomlContent, t.testGroupIDs)
-	if err != nil {
-		log.Printf("Error reading test group test IDs: %v\n", err)
-		return nil, fmt.Errorf("error reading test group test IDs: %w", err)
-	}
-
-	log.Println("Reading test group filenames")
-	t.tGroupFnames, err = readTGroupFnames(specVers, problemTomlContent, t.testGroupIDs)
-	if err != nil {
-		log.Printf("Error reading test group filenames: %v\n", err)
-		return nil, fmt.Errorf("error reading test group filenames: %w", err)
+	for _, k := range t.testGroupIDs {
+		for _, fname := range t.tGroupFnames[k] {
+			id, ok := t.testFilenameToID[fname]
+			if !ok {
+				log.Printf("Test group %d references unknown test: %s\n", k, fname)
+				return nil, fmt.Errorf("test group %d references unknown test: %s", k, fname)
+			}
+			t.tGroupTestIDs[k] = append(t.tGroupTestIDs[k], id)
+		}
 	}
 
-	for k, v := range t.tGroupFnames {
-		for _, fname := range v {
-			t.tGroupTestIDs[k] = append(t.tGroupTestIDs[k], t.testFilenameToID[fname])
+	if opts.TestDirGrouping != TestDirGroupingNone {
+		if len(t.testGroupIDs) > 0 {
+			log.Println("Warning: problem.toml defines test groups, ignoring test directory grouping")
+			t.readDiagnostics = append(t.readDiagnostics, ReadDiagnostic{Path: "problem.toml",
+				Message: "test groups are defined, test directory grouping ignored"})
+		} else {
+			log.Println("Creating test groups from test directories")
+			err = t.groupTestsByDir(opts.TestDirGrouping)
+			if err != nil {
+				log.Printf("Error creating test groups from test directories: %v\n", err)
+				return nil, fmt.Errorf("error creating test groups from test directories: %w", err)
+			}
 		}
 	}
 
@@ -257,16 +244,23 @@ func Read(taskRootDirPath string) (*Task, error) {
 	}
 
 	log.Println("Reading MD statements")
-	t.mdStatements, err = readMDStatements(specVers, taskRootDirPath)
+	t.mdStatements, diagnostics, err = readMDStatements(specVers, taskRootDirPath)
 	if err != nil {
 		log.Printf("Error reading MD statements: %v\n", err)
 	}
+	for _, d := range append(diagnostics, t.statementLanguageDiagnostics()...) {
+		log.Printf("Warning: %s\n", d)
+		t.readDiagnostics = append(t.readDiagnostics, d)
+	}
 
-	// read task illustration
-	log.Println("Reading task illustration filename")
-	t.illstrImgFname, err = readIllstrImgFnameFromPToml(problemTomlContent)
+	log.Println("Reading checker")
+	t.checker, err = os.ReadFile(filepath.Join(taskRootDirPath, "evaluation", "checker.cpp"))
 	if err != nil {
-		log.Printf("Error reading task illustration filename: %v\n", err)
+		if !os.IsNotExist(err) {
+			log.Printf("Error reading checker: %v\n", err)
+			return nil, fmt.Errorf("error reading checker: %w", err)
+		}
+		t.checker = nil
 	}
 
 	log.Println("Reading all assets")
@@ -275,55 +269,34 @@ func Read(taskRootDirPath string) (*Task, error) {
 		log.Printf("Error reading all assets: %v\n", err)
 	}
 
-	log.Println("Reading origin notes")
-	t.OriginNotes, err = readOriginNotes(problemTomlContent)
+	log.Println("Reading editorials")
+	t.editorials, err = readEditorials(taskRootDirPath)
 	if err != nil {
-		log.Printf("Error reading origin notes: %v\n", err)
+		log.Printf("Error reading editorials: %v\n", err)
+		return nil, fmt.Errorf("error reading editorials: %w", err)
+	}
+	for lang := range t.editorials {
+		if _, err := normalizeLanguageCode(lang); err != nil {
+			d := ReadDiagnostic{Path: "editorial/" + lang, Message: err.Error()}
+			log.Printf("Warning: %s\n", d)
+			t.readDiagnostics = append(t.readDiagnostics, d)
+		}
 	}
-	log.Println("Reading task name")
 
-	log.Println("Reading visible input subtasks")
-	t.visibleInputSubtasks, err = readVisibleInputSubtasks(specVers, problemTomlContent)
-	if err != nil {
-		log.Printf("Error reading visible input subtasks: %v\n", err)
+	if len(opts.RequiredLanguages) > 0 {
+		err = t.CheckRequiredLanguages(opts.RequiredLanguages)
+		if err != nil {
+			log.Printf("Error checking required statement languages: %v\n", err)
+			return nil, fmt.Errorf("error checking required statement languages: %w", err)
+		}
 	}
-	log.Println("Successfully read visible input subtasks")
 
 	log.Println("Successfully read and parsed task")
 	return &t, nil
 }
 
-func readVisibleInputSubtasks(_ string, pToml []byte) ([]int, error) {
-	metadata := struct {
-		VisInpSTs []int `toml:"visible_input_subtasks"`
-	}{}
-
-	err := toml.Unmarshal(pToml, &metadata)
-	if err != nil {
-		log.Printf("Failed to unmarshal the visible input subtasks: %v\n", err)
-		return nil, fmt.Errorf("failed to unmarshal the visible input subtasks: %w", err)
-	}
-
-	return metadata.VisInpSTs, nil
-}
-
-func readOriginNotes(pToml []byte) (map[string]string, error) {
-	type Metadata struct {
-		OriginNotes map[string]string `toml:"origin_notes,omitempty"`
-	}
-	metadata := struct {
-		Metadata Metadata `toml:"metadata"`
-	}{}
-
-	err := toml.Unmarshal(pToml, &metadata)
-	if err != nil {
-		log.Printf("Failed to unmarshal the origin notes: %v\n", err)
-		return nil, fmt.Errorf("failed to unmarshal the origin notes: %w", err)
-	}
-
-	return metadata.Metadata.OriginNotes, nil
-}
-
+// readAssets reads the assets directory recursively. Asset paths are
+// relative to the assets directory and use forward slashes, e.g. "img/fig1.png".
 func readAssets(rootDirPath string) ([]asset, error) {
 	res := make([]asset, 0)
 	dirPath := filepath.Join(rootDirPath, "assets")
@@ -331,68 +304,72 @@ func readAssets(rootDirPath string) ([]asset, error) {
 		return res, nil
 	}
 
-	files, err := os.ReadDir(dirPath)
-	if err != nil {
-		return res, fmt.Errorf("error reading assets directory: %w", err)
-	}
+	err := filepath.WalkDir(dirPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
 
-	for _, f := range files {
-		if f.IsDir() {
-			return nil, fmt.Errorf("directories are currently not supported")
+		rel, err := filepath.Rel(dirPath, path)
+		if err != nil {
+			return err
 		}
-		bytes, err := os.ReadFile(filepath.Join(dirPath, f.Name()))
+
+		bytes, err := os.ReadFile(path)
 		if err != nil {
-			return nil, fmt.Errorf("error reading asset: %w", err)
+			return fmt.Errorf("error reading asset: %w", err)
 		}
 		res = append(res, asset{
-			RelativePath: f.Name(),
+			RelativePath: filepath.ToSlash(rel),
 			Content:      bytes,
 		})
-	}
-
-	return res, nil
-}
-
-func readIllstrImgFnameFromPToml(pToml []byte) (string, error) {
-	illustrationPath := ""
-	tomlStruct := struct {
-		IllstrImgFname string `toml:"illustration_image"`
-	}{}
-
-	err := toml.Unmarshal(pToml, &tomlStruct)
+		return nil
+	})
 	if err != nil {
-		log.Printf("Failed to unmarshal the task name: %v\n", err)
-		return "", fmt.Errorf("failed to unmarshal the task name: %w", err)
+		return nil, fmt.Errorf("error reading assets directory: %w", err)
 	}
 
-	illustrationPath = tomlStruct.IllstrImgFname
-	return illustrationPath, nil
+	return res, nil
 }
 
-func readMDStatements(_ string, rootDirPath string) ([]mDStatement, error) {
+// readMDStatements reads the statements, skipped directories and files are
+// returned as diagnostics
+func readMDStatements(_ SpecVersion, rootDirPath string) ([]mDStatement, []ReadDiagnostic, error) {
 	mdDirPath := filepath.Join(rootDirPath, "statements", "md")
 
 	res := make([]mDStatement, 0)
+	diagnostics := make([]ReadDiagnostic, 0)
 	if _, err := os.Stat(mdDirPath); os.IsNotExist(err) {
 		log.Println("MD directory does not exist")
-		return res, nil
+		return res, diagnostics, nil
 		// return res, fmt.Errorf("md directory does not exist: %s", mdDirPath)
 	}
 
 	// statements -> md -> [language] -> {story.md,input.md,output.md}
 	langs, err := os.ReadDir(mdDirPath)
 	if err != nil {
-		return res, fmt.Errorf("error reading md directory: %w", err)
+		return res, diagnostics, fmt.Errorf("error reading md directory: %w", err)
 	}
 
+	seen := make(map[string]bool)
 	for _, lang := range langs {
 		if !lang.IsDir() {
 			continue
 		}
+		// valid tags are normalized, so that lookups find en-us as en-US
+		langStr := lookupLanguageCode(lang.Name())
+		if seen[langStr] {
+			diagnostics = append(diagnostics, ReadDiagnostic{Path: "statements/md/" + lang.Name(),
+				Message: fmt.Sprintf("statement of language %s already read", langStr)})
+			continue
+		}
+		seen[langStr] = true
 
 		files, err := os.ReadDir(filepath.Join(mdDirPath, lang.Name()))
 		if err != nil {
-			return res, fmt.Errorf("error reading md directory: %w", err)
+			return res, diagnostics, fmt.Errorf("error reading md directory: %w", err)
 		}
 
 		res2 := mDStatement{
@@ -403,7 +380,6 @@ func readMDStatements(_ string, rootDirPath string) ([]mDStatement, error) {
 			Notes:    nil, // string pointer
 			Scoring:  nil, // string pointer
 		}
-		langStr := lang.Name()
 		res2.Language = &langStr
 		for _, f := range files {
 			if !strings.HasSuffix(f.Name(), ".md") {
@@ -412,7 +388,7 @@ func readMDStatements(_ string, rootDirPath string) ([]mDStatement, error) {
 
 			content, err := os.ReadFile(filepath.Join(mdDirPath, lang.Name(), f.Name()))
 			if err != nil {
-				return nil, fmt.Errorf("error reading md file: %w", err)
+				return nil, diagnostics, fmt.Errorf("error reading md file: %w", err)
 			}
 
 			switch f.Name() {
@@ -426,20 +402,31 @@ func readMDStatements(_ string, rootDirPath string) ([]mDStatement, error) {
 				res2.Notes = &([]string{string(content)}[0])
 			case "scoring.md":
 				res2.Scoring = &([]string{string(content)}[0])
+			default:
+				name := strings.TrimSuffix(f.Name(), ".md")
+				if err := validateStatementSectionName(name); err != nil {
+					diagnostics = append(diagnostics, ReadDiagnostic{
+						Path: "statements/md/" + lang.Name() + "/" + f.Name(), Message: err.Error()})
+					continue
+				}
+				if res2.Sections == nil {
+					res2.Sections = map[string]string{}
+				}
+				res2.Sections[name] = string(content)
 			}
 		}
 
 		if res2.Story == "" || res2.Input == "" || res2.Output == "" {
-			return nil, fmt.Errorf("invalid MD statement: %+v", res2)
+			return nil, diagnostics, fmt.Errorf("invalid MD statement: %+v", res2)
 		}
 
 		res = append(res, res2)
 	}
 
-	return res, nil
+	return res, diagnostics, nil
 }
 
-func readPDFStatements(_ string, rootDirPath string) (map[string][]byte, error) {
+func readPDFStatements(_ SpecVersion, rootDirPath string) (map[string][]byte, error) {
 	pdfDirPath := filepath.Join(rootDirPath, "statements", "pdf")
 
 	res := make(map[string][]byte)
@@ -464,34 +451,13 @@ func readPDFStatements(_ string, rootDirPath string) (map[string][]byte, error)
 			return nil, fmt.Errorf("error reading pdf file: %w", err)
 		}
 
-		res[f.Name()[0:len(f.Name())-4]] = content
+		lang := lookupLanguageCode(f.Name()[0 : len(f.Name())-4])
+		if _, ok := res[lang]; ok {
+			log.Printf("Skipping PDF statement %s: language %s already read\n", f.Name(), lang)
+			continue
+		}
+		res[lang] = content
 	}
 
 	return res, nil
 }
-
-func readTaskName(specVers string, tomlContent string) (string, error) {
-	log.Printf("Reading task name for specification version: %s\n", specVers)
-	cmpres, err := largerOrEqualSemVersionThan(specVers, "2.2")
-	if err != nil {
-		log.Printf("Error comparing semversions: %v\n", err)
-		return "", fmt.Errorf("error comparing semversions: %w", err)
-	}
-	if !cmpres {
-		log.Printf("Unsupported specification version: %s\n", specVers)
-		return "", fmt.Errorf("unsupported specification version: %s", specVers)
-	}
-
-	tomlStruct := struct {
-		TaskName string `toml:"task_name"`
-	}{}
-
-	err = toml.Unmarshal([]byte(tomlContent), &tomlStruct)
-	if err != nil {
-		log.Printf("Failed to unmarshal the task name: %v\n", err)
-		return "", fmt.Errorf("failed to unmarshal the task name: %w", err)
-	}
-
-	log.Printf("Successfully read task name: %s\n", tomlStruct.TaskName)
-	return tomlStruct.TaskName, nil
-}
@@ -0,0 +1,101 @@
+package fstaskparser_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/programme-lv/fs-task-format-parser/pkg/fstaskparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadingOlderSpecificationVersion(t *testing.T) {
+	parsedTask, err := fstaskparser.Read(testTaskPath)
+	require.NoErrorf(t, err, "failed to read task: %v", err)
+
+	tmpDirectory, err := os.MkdirTemp("", "fstaskparser-test-")
+	require.NoErrorf(t, err, "failed to create temporary directory: %v", err)
+	defer os.RemoveAll(tmpDirectory)
+
+	outputDirectory := filepath.Join(tmpDirectory, "kvadrputekl")
+	err = parsedTask.Store(outputDirectory)
+	require.NoErrorf(t, err, "failed to store task: %v", err)
+
+	// origin notes and test_ids are read from v2.2.0 files as well
+	pTomlPath := filepath.Join(outputDirectory, "problem.toml")
+	pToml, err := os.ReadFile(pTomlPath)
+	require.NoErrorf(t, err, "failed to read problem.toml: %v", err)
+	oldPToml := strings.Replace(string(pToml), "v2.5.0", "2.2", 1)
+	oldPToml = strings.Replace(oldPToml, "test_filenames = ['kp02a', 'kp02b', 'kp02c']", "test_ids = [4, 5, 6]", 1)
+	err = os.WriteFile(pTomlPath, []byte(oldPToml), 0644)
+	require.NoErrorf(t, err, "failed to write problem.toml: %v", err)
+
+	oldTask, err := fstaskparser.Read(outputDirectory)
+	require.NoErrorf(t, err, "failed to read task: %v", err)
+
+	assert.Equal(t, parsedTask.GetTaskName(), oldTask.GetTaskName())
+	assert.Equal(t, 0.5, oldTask.GetCPUTimeLimitInSeconds())
+	assert.Equal(t, []int{1, 2}, oldTask.GetTestGroupIDs())
+	assert.Equal(t, []int{4, 5, 6}, oldTask.GetInfoOnTestGroup(2).TestIDs)
+	assert.Equal(t, parsedTask.GetOriginNotes(), oldTask.GetOriginNotes())
+	assert.NotEmpty(t, oldTask.GetOriginNotes())
+
+	_, err = fstaskparser.ReadWithOptions(outputDirectory, fstaskparser.ReadOptions{Strict: true})
+	require.NoErrorf(t, err, "failed to read task in strict mode: %v", err)
+
+	// statement_sections were introduced in v2.5.0
+	err = os.WriteFile(pTomlPath, []byte(oldPToml+"\n[statement_sections]\nhints = 'staff'\n"), 0644)
+	require.NoErrorf(t, err, "failed to write problem.toml: %v", err)
+	_, err = fstaskparser.ReadWithOptions(outputDirectory, fstaskparser.ReadOptions{Strict: true})
+	var unknownKeysErr *fstaskparser.UnknownKeysError
+	require.True(t, errors.As(err, &unknownKeysErr))
+	assert.Equal(t, "statement_sections", unknownKeysErr.Keys[0].Key)
+
+	// versions before v2.2.0 are not supported
+	err = os.WriteFile(pTomlPath, []byte(strings.Replace(oldPToml, "'2.2'", "'2.1'", 1)), 0644)
+	require.NoErrorf(t, err, "failed to write problem.toml: %v", err)
+	_, err = fstaskparser.Read(outputDirectory)
+	require.Error(t, err)
+}
+
+func BenchmarkReadManyTestGroups(b *testing.B) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	const groupCount = 500
+	const testsPerGroup = 4
+
+	task, err := fstaskparser.NewTask("benchmark")
+	require.NoErrorf(b, err, "failed to create task: %v", err)
+
+	for g := 0; g < groupCount; g++ {
+		testIDs := make([]int, 0, testsPerGroup)
+		for i := 0; i < testsPerGroup; i++ {
+			id := task.AddTest([]byte(fmt.Sprintf("%d %d\n", g, i)), []byte("0\n"))
+			task.AssignFilenameToTest(fmt.Sprintf("%04d%c", g, 'a'+i), id)
+			testIDs = append(testIDs, id)
+		}
+		task.AddTestGroup(1, g%2 == 0, testIDs, g/10+1)
+	}
+
+	taskDir := filepath.Join(b.TempDir(), "benchmark")
+	err = task.Store(taskDir)
+	require.NoErrorf(b, err, "failed to store task: %v", err)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		readTask, err := fstaskparser.Read(taskDir)
+		if err != nil {
+			b.Fatalf("failed to read task: %v", err)
+		}
+		if len(readTask.GetTestGroupIDs()) != groupCount {
+			b.Fatalf("expected %d groups, got %d", groupCount, len(readTask.GetTestGroupIDs()))
+		}
+	}
+}
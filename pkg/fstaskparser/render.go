@@ -0,0 +1,266 @@
+package fstaskparser
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// RenderOptions control how a Markdown statement is rendered to HTML.
+type RenderOptions struct {
+	// AssetURLPrefix rewrites asset image links to AssetURLPrefix + asset path,
+	// e.g. "https://cdn.example.com/tasks/kvadrputekl/". If empty, asset
+	// images are inlined as data URIs.
+	AssetURLPrefix string
+
+	// Fragment renders only the <article> element without the surrounding document.
+	Fragment bool
+
+	// IncludeStaffSections also renders additional statement sections
+	// visible to staff only, such as hints and editorial.
+	IncludeStaffSections bool
+
+	// IncludeEditorial appends the Markdown editorial of the language, if any.
+	// Editorial images are always inlined as data URIs.
+	IncludeEditorial bool
+}
+
+// statementLabels are section headings by statement language
+var statementLabels = map[string]map[string]string{
+	"en": {
+		"input":    "Input",
+		"output":   "Output",
+		"examples": "Examples",
+		"notes":    "Notes",
+		"scoring":  "Scoring",
+		"time":     "Time limit",
+		"memory":   "Memory limit",
+
+		"constraints": "Constraints",
+		"interaction": "Interaction",
+		"hints":       "Hints",
+		"editorial":   "Editorial",
+	},
+	"lv": {
+		"input":    "Ievaddati",
+		"output":   "Izvaddati",
+		"examples": "Piemēri",
+		"notes":    "Piezīmes",
+		"scoring":  "Vērtēšana",
+		"time":     "Laika ierobežojums",
+		"memory":   "Atmiņas ierobežojums",
+
+		"constraints": "Ierobežojumi",
+		"interaction": "Interakcija",
+		"hints":       "Padomi",
+		"editorial":   "Risinājuma apraksts",
+	},
+}
+
+func getStatementLabels(lang string) map[string]string {
+	if labels, ok := statementLabels[lang]; ok {
+		return labels
+	}
+	return statementLabels["en"]
+}
+
+// RenderStatementHTML builds a single HTML document from the Markdown statement
+// of the given language: limits, story, input, output, additional sections
+// visible to contestants, examples with their notes, notes and scoring. Statement placeholders are resolved, math is
+// left in its `$...$` form for KaTeX.
+func (t *Task) RenderStatementHTML(lang string, opts RenderOptions) ([]byte, error) {
+	lang = lookupLanguageCode(lang)
+	i := t.findMarkdownStatement(lang)
+	if i < 0 {
+		return nil, fmt.Errorf("markdown statement for language %s not found", lang)
+	}
+	st := &t.mdStatements[i]
+
+	labels := getStatementLabels(lang)
+	var buf bytes.Buffer
+
+	if !opts.Fragment {
+		fmt.Fprintf(&buf, "<!DOCTYPE html>\n<html lang=\"%s\">\n<head>\n<meta charset=\"utf-8\">\n<title>%s</title>\n</head>\n<body>\n",
+			html.EscapeString(lang), html.EscapeString(t.taskName))
+	}
+
+	buf.WriteString("<article class=\"statement\">\n")
+	fmt.Fprintf(&buf, "<h1>%s</h1>\n", html.EscapeString(t.taskName))
+	fmt.Fprintf(&buf, "<p class=\"limits\">%s: %s s<br>\n%s: %d MB</p>\n",
+		html.EscapeString(labels["time"]), strconv.FormatFloat(t.cpuTimeSeconds, 'f', -1, 64),
+		html.EscapeString(labels["memory"]), t.memoryMegabytes)
+
+	sections := []struct {
+		class   string
+		heading string
+		md      *string
+	}{
+		{"story", "", &st.Story},
+		{"input", labels["input"], &st.Input},
+		{"output", labels["output"], &st.Output},
+	}
+	for _, s := range sections {
+		err := t.renderStatementSection(&buf, s.class, s.heading, *s.md, opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, name := range sortedSectionNames(st.Sections) {
+		if t.GetStatementSectionVisibility(name) != SectionVisibleToContestants && !opts.IncludeStaffSections {
+			continue
+		}
+		heading, ok := labels[name]
+		if !ok {
+			heading = name
+		}
+		err := t.renderStatementSection(&buf, name, heading, st.Sections[name], opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(t.examples) > 0 {
+		fmt.Fprintf(&buf, "<section class=\"examples\">\n<h2>%s</h2>\n", html.EscapeString(labels["examples"]))
+		for _, e := range t.examples {
+			buf.WriteString("<div class=\"example\">\n")
+			fmt.Fprintf(&buf, "<pre class=\"example-input\">%s</pre>\n", html.EscapeString(string(e.Input)))
+			fmt.Fprintf(&buf, "<pre class=\"example-output\">%s</pre>\n", html.EscapeString(string(e.Output)))
+			if len(e.MdNote) > 0 {
+				err := t.renderStatementSection(&buf, "example-note", "", string(e.MdNote), opts)
+				if err != nil {
+					return nil, err
+				}
+			}
+			buf.WriteString("</div>\n")
+		}
+		buf.WriteString("</section>\n")
+	}
+
+	if st.Notes != nil {
+		err := t.renderStatementSection(&buf, "notes", labels["notes"], *st.Notes, opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if st.Scoring != nil {
+		err := t.renderStatementSection(&buf, "scoring", labels["scoring"], *st.Scoring, opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.IncludeEditorial {
+		if e, ok := t.editorials[lang]; ok && e.Markdown != nil {
+			err := t.renderSection(&buf, "editorial", labels["editorial"], *e.Markdown, e.Assets, "")
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	buf.WriteString("</article>\n")
+	if !opts.Fragment {
+		buf.WriteString("</body>\n</html>\n")
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (t *Task) renderStatementSection(buf *bytes.Buffer, class string, heading string, md string, opts RenderOptions) error {
+	return t.renderSection(buf, class, heading, md, t.assets, opts.AssetURLPrefix)
+}
+
+func (t *Task) renderSection(buf *bytes.Buffer, class string, heading string, md string, assets []asset, assetURLPrefix string) error {
+	fmt.Fprintf(buf, "<section class=\"%s\">\n", class)
+	if heading != "" {
+		fmt.Fprintf(buf, "<h2>%s</h2>\n", html.EscapeString(heading))
+	}
+	rendered, err := t.renderMarkdown(md, assets, assetURLPrefix)
+	if err != nil {
+		return fmt.Errorf("error rendering %s: %w", class, err)
+	}
+	buf.Write(rendered)
+	buf.WriteString("</section>\n")
+	return nil
+}
+
+var markdownRenderer = goldmark.New(goldmark.WithExtensions(extension.Table, extension.Strikethrough))
+
+// renderMarkdown resolves statement placeholders and converts Markdown to
+// HTML. Math spans are replaced by markers during conversion so that
+// emphasis and escapes inside formulas are not interpreted as Markdown.
+func (t *Task) renderMarkdown(md string, assets []asset, assetURLPrefix string) ([]byte, error) {
+	md = t.resolveTemplate(md)
+	spans := findMathSpans(md)
+	formulas := make([]string, 0, len(spans))
+	var src strings.Builder
+	last := 0
+	for _, s := range spans {
+		if s.Unclosed {
+			continue
+		}
+		src.WriteString(md[last:s.Start])
+		fmt.Fprintf(&src, "FSTASKMATH%dX", len(formulas))
+		formulas = append(formulas, md[s.Start:s.End])
+		last = s.End
+	}
+	src.WriteString(md[last:])
+
+	var out bytes.Buffer
+	err := markdownRenderer.Convert([]byte(rewriteAssetLinks(src.String(), assets, assetURLPrefix)), &out)
+	if err != nil {
+		return nil, err
+	}
+
+	res := out.String()
+	for i := len(formulas) - 1; i >= 0; i-- {
+		res = strings.Replace(res, fmt.Sprintf("FSTASKMATH%dX", i), html.EscapeString(formulas[i]), 1)
+	}
+	return []byte(res), nil
+}
+
+// rewriteAssetLinks points image links to assets either to a data URI or to assetURLPrefix
+func rewriteAssetLinks(md string, assets []asset, assetURLPrefix string) string {
+	byPath := make(map[string]asset, len(assets))
+	for _, a := range assets {
+		byPath[a.RelativePath] = a
+	}
+
+	var res strings.Builder
+	last := 0
+	for _, m := range mdImageLinkRegexp.FindAllStringSubmatchIndex(md, -1) {
+		target := md[m[2]:m[3]]
+		a, ok := byPath[normalizeAssetPath(target)]
+		if !ok || urlSchemeRegexp.MatchString(target) {
+			continue
+		}
+
+		res.WriteString(md[last:m[2]])
+		if assetURLPrefix != "" {
+			res.WriteString(assetURLPrefix + a.RelativePath)
+		} else {
+			res.WriteString(assetDataURI(a))
+		}
+		last = m[3]
+	}
+	res.WriteString(md[last:])
+	return res.String()
+}
+
+func assetDataURI(a asset) string {
+	mimeType := mime.TypeByExtension(path.Ext(a.RelativePath))
+	if mimeType == "" {
+		mimeType = http.DetectContentType(a.Content)
+	}
+	return fmt.Sprintf("data:%s;base64,%s", mimeType, base64.StdEncoding.EncodeToString(a.Content))
+}
@@ -0,0 +1,58 @@
+package fstaskparser_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/programme-lv/fs-task-format-parser/pkg/fstaskparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderingStatementHTML(t *testing.T) {
+	parsedTask, err := fstaskparser.Read(testTaskPath)
+	require.NoErrorf(t, err, "failed to read task: %v", err)
+
+	err = parsedTask.AddAsset("example1.png", []byte("\x89PNG\r\n\x1a\n"))
+	require.NoErrorf(t, err, "failed to add asset: %v", err)
+	for _, path := range []string{"../../x.png", "/x.png", `img\x.png`, "img/../x.png", ""} {
+		require.Errorf(t, parsedTask.AddAsset(path, nil), "asset path %q", path)
+	}
+
+	rendered, err := parsedTask.RenderStatementHTML("lv", fstaskparser.RenderOptions{})
+	require.NoErrorf(t, err, "failed to render statement: %v", err)
+	doc := string(rendered)
+
+	assert.True(t, strings.HasPrefix(doc, "<!DOCTYPE html>"))
+	assert.Contains(t, doc, `<html lang="lv">`)
+	assert.Contains(t, doc, "<h1>Kvadrātveida putekļsūcējs</h1>")
+	assert.Contains(t, doc, "Laika ierobežojums: 0.5 s")
+	assert.Contains(t, doc, "Atmiņas ierobežojums: 256 MB")
+	assert.Contains(t, doc, "<h2>Ievaddati</h2>")
+	assert.Contains(t, doc, "<h2>Vērtēšana</h2>")
+	assert.Contains(t, doc, "<table>")
+
+	// math is kept verbatim for KaTeX
+	assert.Contains(t, doc, `$N \times M$`)
+	assert.Contains(t, doc, `$(1 \leq K \leq \min(N, M))$`)
+
+	// examples with their notes
+	examples := parsedTask.GetExamples()
+	assert.Contains(t, doc, "<pre class=\"example-input\">"+string(examples[0].Input)+"</pre>")
+	assert.Contains(t, doc, "hehe te ir kaut kas $N = 5$")
+
+	// existing assets are inlined, missing ones left as they are
+	assert.Contains(t, doc, `src="data:image/png;base64,iVBORw0KGgo="`)
+	assert.Contains(t, doc, `src="example2.png"`)
+
+	fragment, err := parsedTask.RenderStatementHTML("lv", fstaskparser.RenderOptions{
+		AssetURLPrefix: "https://cdn.example.com/kvadrputekl/",
+		Fragment:       true,
+	})
+	require.NoErrorf(t, err, "failed to render statement: %v", err)
+	assert.True(t, strings.HasPrefix(string(fragment), "<article"))
+	assert.Contains(t, string(fragment), `src="https://cdn.example.com/kvadrputekl/example1.png"`)
+
+	_, err = parsedTask.RenderStatementHTML("en", fstaskparser.RenderOptions{})
+	require.Error(t, err)
+}
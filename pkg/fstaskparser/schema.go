@@ -0,0 +1,260 @@
+package fstaskparser
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// problemTomlSchema describes the problem.toml layout of a range of
+// specification versions. All differences between versions live here:
+// each schema decodes into its own struct and is then upgraded to the
+// latest layout, ProblemTOML, which is mapped into Task.
+type problemTomlSchema struct {
+	since     SpecVersion
+	newTarget func() any                    // returns a pointer to the schema struct
+	toLatest  func(decoded any) ProblemTOML // upgrades the decoded schema struct
+}
+
+// problemTomlSchemas is ordered by increasing version.
+var problemTomlSchemas = []problemTomlSchema{
+	{
+		since:     specVersion2_2,
+		newTarget: func() any { return &problemTomlV2_2{} },
+		toLatest:  func(decoded any) ProblemTOML { return decoded.(*problemTomlV2_2).toLatest() },
+	},
+	{
+		since:     specVersion2_3,
+		newTarget: func() any { return &problemTomlV2_3{} },
+		toLatest:  func(decoded any) ProblemTOML { return decoded.(*problemTomlV2_3).toLatest() },
+	},
+	{
+		since:     specVersion2_4,
+		newTarget: func() any { return &problemTomlV2_4{} },
+		toLatest:  func(decoded any) ProblemTOML { return decoded.(*problemTomlV2_4).toLatest() },
+	},
+	{
+		since:     specVersion2_5,
+		newTarget: func() any { return &ProblemTOML{} },
+		toLatest:  func(decoded any) ProblemTOML { return *decoded.(*ProblemTOML) },
+	},
+}
+
+func getProblemTomlSchema(specVers SpecVersion) (*problemTomlSchema, error) {
+	for i := len(problemTomlSchemas) - 1; i >= 0; i-- {
+		if specVers.AtLeast(problemTomlSchemas[i].since) {
+			return &problemTomlSchemas[i], nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported specification version: %s", specVers)
+}
+
+// decodeProblemToml decodes problem.toml with the schema of its specification
+// version. In strict mode unknown keys are reported as *UnknownKeysError.
+func decodeProblemToml(specVers SpecVersion, pToml []byte, strict bool) (ProblemTOML, error) {
+	schema, err := getProblemTomlSchema(specVers)
+	if err != nil {
+		log.Printf("Error finding problem.toml schema: %v\n", err)
+		return ProblemTOML{}, err
+	}
+
+	target := schema.newTarget()
+	decoder := toml.NewDecoder(bytes.NewReader(pToml))
+	if strict {
+		decoder = decoder.DisallowUnknownFields()
+	}
+
+	err = decoder.Decode(target)
+	if err != nil {
+		var strictErr *toml.StrictMissingError
+		if errors.As(err, &strictErr) {
+			return ProblemTOML{}, newUnknownKeysError(strictErr, reflect.TypeOf(target).Elem())
+		}
+		log.Printf("Failed to decode problem.toml: %v\n", err)
+		return ProblemTOML{}, fmt.Errorf("failed to decode problem.toml: %w", err)
+	}
+
+	return schema.toLatest(target), nil
+}
+
+// pTomlMetadataV2_2 already has the origin notes, read before they were
+// documented in v2.4.0
+type pTomlMetadataV2_2 struct {
+	ProblemTags        []string          `toml:"problem_tags"`
+	DifficultyFrom1To5 int               `toml:"difficulty_1_to_5"`
+	TaskAuthors        []string          `toml:"task_authors"`
+	OriginOlympiad     string            `toml:"origin_olympiad"`
+	OriginNotes        map[string]string `toml:"origin_notes,omitempty"`
+	OriginInstitution  string            `toml:"origin_institution,omitempty"`
+}
+
+func (m pTomlMetadataV2_2) toLatest() PTomlMetadata {
+	return PTomlMetadata{
+		ProblemTags:        m.ProblemTags,
+		DifficultyFrom1To5: m.DifficultyFrom1To5,
+		TaskAuthors:        m.TaskAuthors,
+		OriginOlympiad:     m.OriginOlympiad,
+		OriginNotes:        m.OriginNotes,
+		OriginInstitution:  m.OriginInstitution,
+	}
+}
+
+type pTomlTestGroupV2_2 struct {
+	GroupID    int      `toml:"group_id"`
+	Points     int      `toml:"points"`
+	Public     bool     `toml:"public"`
+	Subtask    int      `toml:"subtask,omitempty"`
+	TestIDs    []int    `toml:"test_ids,omitempty"`
+	TestFnames []string `toml:"test_filenames,omitempty"`
+}
+
+// problemTomlV2_2 is the oldest supported version, without `test_id_overwrite`.
+type problemTomlV2_2 struct {
+	Specification        string               `toml:"specification"`
+	TaskName             string               `toml:"task_name"`
+	Metadata             pTomlMetadataV2_2    `toml:"metadata"`
+	Constraints          PTomlConstraints     `toml:"constraints"`
+	TestGroups           []pTomlTestGroupV2_2 `toml:"test_groups"`
+	IllustrationImgFname string               `toml:"illustration_image,omitempty"`
+	VisInpSTs            []int                `toml:"visible_input_subtasks"`
+}
+
+func (p *problemTomlV2_2) toLatest() ProblemTOML {
+	groups := make([]PTomlTestGroup, 0, len(p.TestGroups))
+	for _, g := range p.TestGroups {
+		groups = append(groups, PTomlTestGroup{
+			GroupID:    g.GroupID,
+			Points:     g.Points,
+			Public:     g.Public,
+			Subtask:    g.Subtask,
+			TestIDs:    g.TestIDs,
+			TestFnames: g.TestFnames,
+		})
+	}
+
+	return ProblemTOML{
+		Specification:        p.Specification,
+		TaskName:             p.TaskName,
+		Metadata:             p.Metadata.toLatest(),
+		Constraints:          p.Constraints,
+		TestGroups:           groups,
+		IllustrationImgFname: p.IllustrationImgFname,
+		VisInpSTs:            p.VisInpSTs,
+	}
+}
+
+// problemTomlV2_3 adds `test_id_overwrite`.
+type problemTomlV2_3 struct {
+	Specification        string            `toml:"specification"`
+	TaskName             string            `toml:"task_name"`
+	Metadata             pTomlMetadataV2_2 `toml:"metadata"`
+	Constraints          PTomlConstraints  `toml:"constraints"`
+	TestGroups           []PTomlTestGroup  `toml:"test_groups"`
+	IllustrationImgFname string            `toml:"illustration_image,omitempty"`
+	VisInpSTs            []int             `toml:"visible_input_subtasks"`
+	TestIDOverwrite      map[string]int    `toml:"test_id_overwrite,omitempty"`
+}
+
+func (p *problemTomlV2_3) toLatest() ProblemTOML {
+	return ProblemTOML{
+		Specification:        p.Specification,
+		TaskName:             p.TaskName,
+		Metadata:             p.Metadata.toLatest(),
+		Constraints:          p.Constraints,
+		TestGroups:           p.TestGroups,
+		IllustrationImgFname: p.IllustrationImgFname,
+		VisInpSTs:            p.VisInpSTs,
+		TestIDOverwrite:      p.TestIDOverwrite,
+	}
+}
+
+// problemTomlV2_4 adds `metadata.origin_notes` and `metadata.origin_institution`.
+type problemTomlV2_4 struct {
+	Specification        string           `toml:"specification"`
+	TaskName             string           `toml:"task_name"`
+	Metadata             PTomlMetadata    `toml:"metadata"`
+	Constraints          PTomlConstraints `toml:"constraints"`
+	TestGroups           []PTomlTestGroup `toml:"test_groups"`
+	IllustrationImgFname string           `toml:"illustration_image,omitempty"`
+	VisInpSTs            []int            `toml:"visible_input_subtasks"`
+	TestIDOverwrite      map[string]int   `toml:"test_id_overwrite,omitempty"`
+}
+
+func (p *problemTomlV2_4) toLatest() ProblemTOML {
+	return ProblemTOML{
+		Specification:        p.Specification,
+		TaskName:             p.TaskName,
+		Metadata:             p.Metadata,
+		Constraints:          p.Constraints,
+		TestGroups:           p.TestGroups,
+		IllustrationImgFname: p.IllustrationImgFname,
+		VisInpSTs:            p.VisInpSTs,
+		TestIDOverwrite:      p.TestIDOverwrite,
+	}
+}
+
+// applyProblemTOML maps the decoded problem.toml into the task. Test group
+// filenames are resolved to IDs later, once the tests directory is read.
+func (t *Task) applyProblemTOML(p ProblemTOML) error {
+	t.taskName = p.TaskName
+	t.cpuTimeSeconds = p.Constraints.CPUTimeSeconds
+	t.memoryMegabytes = p.Constraints.MemoryMegabytes
+
+	t.problemTags = p.Metadata.ProblemTags
+	t.problemAuthors = p.Metadata.TaskAuthors
+	t.originOlympiad = p.Metadata.OriginOlympiad
+	t.difficultyOneToFive = p.Metadata.DifficultyFrom1To5
+	t.OriginNotes = p.Metadata.OriginNotes
+	t.OriginInstitution = p.Metadata.OriginInstitution
+
+	t.illstrImgFname = p.IllustrationImgFname
+	t.visibleInputSubtasks = p.VisInpSTs
+
+	t.testIDOverwrite = p.TestIDOverwrite
+	if t.testIDOverwrite == nil {
+		t.testIDOverwrite = map[string]int{}
+	}
+
+	t.statementSectionVisibility = make(map[string]SectionVisibility, len(p.StatementSections))
+	for name, v := range p.StatementSections {
+		err := t.SetStatementSectionVisibility(name, SectionVisibility(v))
+		if err != nil {
+			log.Printf("Invalid statement section declaration: %v\n", err)
+			return err
+		}
+	}
+
+	t.editorialRelease = EditorialReleaseAfterContest
+	if p.Editorial != nil {
+		err := t.SetEditorialRelease(EditorialRelease(p.Editorial.Release))
+		if err != nil {
+			log.Printf("Invalid editorial release: %v\n", err)
+			return err
+		}
+	}
+
+	t.testGroupIDs = make([]int, 0, len(p.TestGroups))
+	t.isTGroupPublic = make(map[int]bool, len(p.TestGroups))
+	t.tGroupPoints = make(map[int]int, len(p.TestGroups))
+	t.tGroupToStMap = make(map[int]int, len(p.TestGroups))
+	t.tGroupTestIDs = make(map[int][]int, len(p.TestGroups))
+	t.tGroupFnames = make(map[int][]string, len(p.TestGroups))
+	for _, g := range p.TestGroups {
+		if _, ok := t.tGroupToStMap[g.GroupID]; ok {
+			log.Printf("Duplicate group ID found: %d\n", g.GroupID)
+			return fmt.Errorf("duplicate group ID: %d", g.GroupID)
+		}
+		t.testGroupIDs = append(t.testGroupIDs, g.GroupID)
+		t.isTGroupPublic[g.GroupID] = g.Public
+		t.tGroupPoints[g.GroupID] = g.Points
+		t.tGroupToStMap[g.GroupID] = g.Subtask
+		t.tGroupTestIDs[g.GroupID] = append([]int{}, g.TestIDs...)
+		t.tGroupFnames[g.GroupID] = append([]string{}, g.TestFnames...)
+	}
+
+	return nil
+}
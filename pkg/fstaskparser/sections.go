@@ -0,0 +1,92 @@
+package fstaskparser
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// SectionVisibility tells who may see an additional statement section.
+type SectionVisibility string
+
+const (
+	// SectionVisibleToContestants sections are part of the statement shown in the contest
+	SectionVisibleToContestants SectionVisibility = "contestants"
+	// SectionVisibleToStaff sections are stored with the task but kept private
+	SectionVisibleToStaff SectionVisibility = "staff"
+)
+
+// DefaultStatementSections are the additional statement sections known
+// without being declared in problem.toml. Sections not listed here and
+// not declared in `statement_sections` are visible to staff only.
+var DefaultStatementSections = map[string]SectionVisibility{
+	"constraints": SectionVisibleToContestants,
+	"interaction": SectionVisibleToContestants,
+	"hints":       SectionVisibleToStaff,
+	"editorial":   SectionVisibleToStaff,
+}
+
+// coreStatementSections are stored in their own mDStatement fields
+var coreStatementSections = map[string]bool{
+	"story":   true,
+	"input":   true,
+	"output":  true,
+	"notes":   true,
+	"scoring": true,
+}
+
+var statementSectionNameRegexp = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+func validateStatementSectionName(name string) error {
+	if coreStatementSections[name] {
+		return fmt.Errorf("statement section %s is not an additional section", name)
+	}
+	if !statementSectionNameRegexp.MatchString(name) {
+		return fmt.Errorf("invalid statement section name %q", name)
+	}
+	return nil
+}
+
+func validateSectionVisibility(v SectionVisibility) error {
+	if v != SectionVisibleToContestants && v != SectionVisibleToStaff {
+		return fmt.Errorf("invalid statement section visibility %q", v)
+	}
+	return nil
+}
+
+// GetStatementSectionVisibility returns who may see an additional statement section
+func (t *Task) GetStatementSectionVisibility(name string) SectionVisibility {
+	if v, ok := t.statementSectionVisibility[name]; ok {
+		return v
+	}
+	if v, ok := DefaultStatementSections[name]; ok {
+		return v
+	}
+	return SectionVisibleToStaff
+}
+
+// SetStatementSectionVisibility declares who may see an additional statement
+// section. The declaration is stored in problem.toml.
+func (t *Task) SetStatementSectionVisibility(name string, visibility SectionVisibility) error {
+	err := validateStatementSectionName(name)
+	if err != nil {
+		return err
+	}
+	err = validateSectionVisibility(visibility)
+	if err != nil {
+		return err
+	}
+
+	t.statementSectionVisibility[name] = visibility
+	return nil
+}
+
+// sortedSectionNames returns the additional section names in alphabetical order
+func sortedSectionNames(sections map[string]string) []string {
+	res := make([]string, 0, len(sections))
+	for name := range sections {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+	return res
+}
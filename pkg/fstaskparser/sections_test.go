@@ -0,0 +1,97 @@
+package fstaskparser_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/programme-lv/fs-task-format-parser/pkg/fstaskparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadingWritingAdditionalStatementSections(t *testing.T) {
+	parsedTask, err := fstaskparser.Read(testTaskPath)
+	require.NoErrorf(t, err, "failed to read task: %v", err)
+
+	lv, err := parsedTask.GetMarkdownStatement("lv")
+	require.NoErrorf(t, err, "failed to get statement: %v", err)
+	assert.Empty(t, lv.Sections)
+
+	lv.Sections = map[string]string{
+		"interaction":     "Programma sazinās ar žūriju.",
+		"hints":           "Izmanto prefiksu summas.",
+		"solution_sketch": "Dinamiskā programmēšana.",
+	}
+	require.NoError(t, parsedTask.SetMarkdownStatement("lv", lv))
+	for _, name := range []string{"story", "../../escape", "Hints"} {
+		bad := lv
+		bad.Sections = map[string]string{name: "X"}
+		require.Errorf(t, parsedTask.SetMarkdownStatement("lv", bad), "section %q accepted", name)
+	}
+	require.NoError(t, parsedTask.SetStatementSectionVisibility("solution_sketch", fstaskparser.SectionVisibleToContestants))
+	require.Error(t, parsedTask.SetStatementSectionVisibility("story", fstaskparser.SectionVisibleToStaff))
+	require.Error(t, parsedTask.SetStatementSectionVisibility("hints", "everyone"))
+
+	assert.Equal(t, fstaskparser.SectionVisibleToContestants, parsedTask.GetStatementSectionVisibility("interaction"))
+	assert.Equal(t, fstaskparser.SectionVisibleToStaff, parsedTask.GetStatementSectionVisibility("hints"))
+	assert.Equal(t, fstaskparser.SectionVisibleToStaff, parsedTask.GetStatementSectionVisibility("unknown"))
+
+	tmpDirectory, err := os.MkdirTemp("", "fstaskparser-test-")
+	require.NoErrorf(t, err, "failed to create temporary directory: %v", err)
+	defer os.RemoveAll(tmpDirectory)
+
+	outputDirectory := filepath.Join(tmpDirectory, "kvadrputekl")
+	err = parsedTask.Store(outputDirectory)
+	require.NoErrorf(t, err, "failed to store task: %v", err)
+
+	for _, fname := range []string{"interaction.md", "hints.md", "solution_sketch.md"} {
+		assert.FileExists(t, filepath.Join(outputDirectory, "statements", "md", "lv", fname))
+	}
+	pToml, err := os.ReadFile(filepath.Join(outputDirectory, "problem.toml"))
+	require.NoErrorf(t, err, "failed to read problem.toml: %v", err)
+	assert.Contains(t, string(pToml), "solution_sketch = 'contestants'")
+
+	// section names that SetMarkdownStatements did not check are rejected by Store
+	escaping := lv
+	escaping.Sections = map[string]string{"../../escape": "X"}
+	badTask, err := fstaskparser.Read(testTaskPath)
+	require.NoErrorf(t, err, "failed to read task: %v", err)
+	badTask.SetMarkdownStatements([]fstaskparser.MarkdownStatement{escaping})
+	require.Error(t, badTask.Store(filepath.Join(tmpDirectory, "bad")))
+	assert.NoFileExists(t, filepath.Join(tmpDirectory, "bad", "statements", "escape.md"))
+
+	// a file that is not a valid section name is skipped with a diagnostic
+	err = os.WriteFile(filepath.Join(outputDirectory, "statements", "md", "lv", "Extra-Notes.md"), []byte("X"), 0644)
+	require.NoErrorf(t, err, "failed to write statement file: %v", err)
+	storedTask, err := fstaskparser.Read(outputDirectory)
+	require.NoErrorf(t, err, "failed to read task: %v", err)
+	diagnostics := storedTask.GetReadDiagnostics()
+	require.Equal(t, 1, len(diagnostics))
+	assert.Equal(t, "statements/md/lv/Extra-Notes.md", diagnostics[0].Path)
+	storedLv, err := storedTask.GetMarkdownStatement("lv")
+	require.NoErrorf(t, err, "failed to get statement: %v", err)
+	assert.Equal(t, lv.Sections, storedLv.Sections)
+	assert.Equal(t, fstaskparser.SectionVisibleToContestants, storedTask.GetStatementSectionVisibility("solution_sketch"))
+
+	coverage := storedTask.LanguageCoverage()
+	assert.Equal(t, []string{"story", "input", "output", "scoring", "hints", "interaction", "solution_sketch"}, coverage[0].MDSections)
+
+	rendered, err := storedTask.RenderStatementHTML("lv", fstaskparser.RenderOptions{Fragment: true})
+	require.NoErrorf(t, err, "failed to render statement: %v", err)
+	assert.Contains(t, string(rendered), "<h2>Interakcija</h2>")
+	assert.Contains(t, string(rendered), "<h2>solution_sketch</h2>")
+	assert.False(t, strings.Contains(string(rendered), "prefiksu summas"))
+
+	rendered, err = storedTask.RenderStatementHTML("lv", fstaskparser.RenderOptions{Fragment: true, IncludeStaffSections: true})
+	require.NoErrorf(t, err, "failed to render statement: %v", err)
+	assert.Contains(t, string(rendered), "<h2>Padomi</h2>")
+
+	// an invalid visibility in problem.toml is rejected
+	err = os.WriteFile(filepath.Join(outputDirectory, "problem.toml"),
+		[]byte(strings.Replace(string(pToml), "'contestants'", "'everyone'", 1)), 0644)
+	require.NoErrorf(t, err, "failed to write problem.toml: %v", err)
+	_, err = fstaskparser.Read(outputDirectory)
+	require.Error(t, err)
+}
@@ -6,98 +6,199 @@ import (
 	"strings"
 )
 
-func largerOrEqualSemVersionThan(a, b string) (bool, error) {
-	cmpRes, err := getCmpSemVersionsResult(a, b)
-	if err != nil {
-		return false, fmt.Errorf("error comparing sem versions: %w", err)
-	}
-
-	return cmpRes >= 0, nil
+// SpecVersion is a parsed problem.toml specification version.
+//
+// Versions follow semantic versioning 2.0 with an optional "v" prefix.
+// Legacy versions such as "2.2" that omit the minor or patch number
+// are accepted and the missing numbers are treated as zero.
+type SpecVersion struct {
+	Major      int
+	Minor      int
+	Patch      int
+	PreRelease []string // dot separated pre-release identifiers, e.g. ["rc", "1"]
+	Build      []string // dot separated build metadata, ignored in comparison
 }
 
-// func smallerOrEqualSemVersionThan(a, b string) (bool, error) {
-// 	cmpRes, err := getCmpSemVersionsResult(a, b)
-// 	if err != nil {
-// 		return false, fmt.Errorf("error comparing sem versions: %w", err)
-// 	}
+// ParseSpecVersion parses a specification version such as "v2.5.0-rc.1+build.7".
+func ParseSpecVersion(s string) (SpecVersion, error) {
+	res := SpecVersion{}
+	if s == "" {
+		return res, fmt.Errorf("empty version string")
+	}
 
-// 	return cmpRes <= 0, nil
-// }
+	rest := strings.TrimPrefix(s, "v")
 
-// getCmpSemVersionsResult compares two semantic versions and returns the result of the comparison.
-//
-// Parameters:
-// - a: the first semantic version to compare.
-// - b: the second semantic version to compare.
-//
-// Returns:
-// - int: the result of the comparison. 1 if a > b, -1 if a < b, 0 if a == b.
-// - error: an error if there was an issue converting the semantic versions to int slices for comparison.
-func getCmpSemVersionsResult(a, b string) (int, error) {
-	aInts, err := formatStringAsIntSliceForComparision(a)
-	if err != nil {
-		msg := fmt.Errorf("error converting to int slice for a: %w", err)
-		return 0, msg
+	if i := strings.IndexByte(rest, '+'); i >= 0 {
+		build, err := parseSemVerIdentifiers(rest[i+1:], false)
+		if err != nil {
+			return res, fmt.Errorf("invalid build metadata in %q: %w", s, err)
+		}
+		res.Build = build
+		rest = rest[:i]
 	}
 
-	bInts, err := formatStringAsIntSliceForComparision(b)
-	if err != nil {
-		msg := fmt.Errorf("error converting to int slice for b: %w", err)
-		return 0, msg
+	if i := strings.IndexByte(rest, '-'); i >= 0 {
+		pre, err := parseSemVerIdentifiers(rest[i+1:], true)
+		if err != nil {
+			return res, fmt.Errorf("invalid pre-release in %q: %w", s, err)
+		}
+		res.PreRelease = pre
+		rest = rest[:i]
+	}
 
+	parts := strings.Split(rest, ".")
+	if len(parts) > 3 {
+		return res, fmt.Errorf("too many version parts in %q", s)
 	}
 
-	cmpRes := compareIntSlices(aInts, bInts)
+	nums := [3]int{}
+	for i, part := range parts {
+		n, err := parseSemVerNumber(part)
+		if err != nil {
+			return res, fmt.Errorf("invalid version part %q in %q: %w", part, s, err)
+		}
+		nums[i] = n
+	}
+	res.Major, res.Minor, res.Patch = nums[0], nums[1], nums[2]
 
-	return cmpRes, nil
+	return res, nil
 }
 
-func formatStringAsIntSliceForComparision(s string) ([]int, error) {
-	// if starts with a "v", remove it
-	if s[0] == 'v' {
-		s = s[1:]
+// MustParseSpecVersion is like ParseSpecVersion but panics on error.
+func MustParseSpecVersion(s string) SpecVersion {
+	v, err := ParseSpecVersion(s)
+	if err != nil {
+		panic(err)
 	}
+	return v
+}
 
-	// if ends with a ".0", remove it
-	for strings.HasSuffix(s, ".0") {
-		s = s[:len(s)-2]
+// Compare returns -1 if v < other, 0 if v == other and 1 if v > other
+// using semantic versioning 2.0 precedence rules.
+func (v SpecVersion) Compare(other SpecVersion) int {
+	if c := compareInts(v.Major, other.Major); c != 0 {
+		return c
+	}
+	if c := compareInts(v.Minor, other.Minor); c != 0 {
+		return c
+	}
+	if c := compareInts(v.Patch, other.Patch); c != 0 {
+		return c
 	}
 
-	if s == "" {
-		return nil, fmt.Errorf("empty string when converting to int slice")
+	// a version without pre-release has higher precedence
+	if len(v.PreRelease) == 0 && len(other.PreRelease) == 0 {
+		return 0
+	}
+	if len(v.PreRelease) == 0 {
+		return 1
+	}
+	if len(other.PreRelease) == 0 {
+		return -1
 	}
 
-	// split by "."
-	parts := strings.Split(s, ".")
-	res := make([]int, len(parts))
-	for i, part := range parts {
-		var err error
-		res[i], err = strconv.Atoi(part)
-		if err != nil {
-			return nil, fmt.Errorf("error converting version part to int: %w", err)
+	for i := 0; i < len(v.PreRelease) && i < len(other.PreRelease); i++ {
+		if c := comparePreReleaseIdentifiers(v.PreRelease[i], other.PreRelease[i]); c != 0 {
+			return c
 		}
 	}
 
-	return res, nil
+	return compareInts(len(v.PreRelease), len(other.PreRelease))
+}
+
+// AtLeast reports whether v has the same or higher precedence than other.
+func (v SpecVersion) AtLeast(other SpecVersion) bool {
+	return v.Compare(other) >= 0
+}
+
+// String formats the version in its canonical "vMAJOR.MINOR.PATCH" form.
+func (v SpecVersion) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "v%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if len(v.PreRelease) > 0 {
+		sb.WriteString("-")
+		sb.WriteString(strings.Join(v.PreRelease, "."))
+	}
+	if len(v.Build) > 0 {
+		sb.WriteString("+")
+		sb.WriteString(strings.Join(v.Build, "."))
+	}
+	return sb.String()
+}
+
+func parseSemVerNumber(s string) (int, error) {
+	if s == "" {
+		return 0, fmt.Errorf("empty number")
+	}
+	if !isASCIIDigits(s) {
+		return 0, fmt.Errorf("not a number")
+	}
+	if len(s) > 1 && s[0] == '0' {
+		return 0, fmt.Errorf("leading zero")
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
 }
 
-// 0 if a == b
-// 1 if a > b
-// -1 if a < b
-func compareIntSlices(a, b []int) int {
-	for i := 0; i < len(a) || i < len(b); i++ {
-		if i >= len(a) { // a is shorter and equal in the common part
-			return -1
+func parseSemVerIdentifiers(s string, forbidLeadingZeros bool) ([]string, error) {
+	ids := strings.Split(s, ".")
+	for _, id := range ids {
+		if id == "" {
+			return nil, fmt.Errorf("empty identifier")
 		}
-		if i >= len(b) { // b is shorter and equal in the common part
-			return 1
+		for _, r := range id {
+			if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '-') {
+				return nil, fmt.Errorf("invalid character %q in identifier %q", r, id)
+			}
 		}
-		if a[i] < b[i] {
-			return -1
+		if forbidLeadingZeros && isASCIIDigits(id) && len(id) > 1 && id[0] == '0' {
+			return nil, fmt.Errorf("leading zero in numeric identifier %q", id)
 		}
-		if a[i] > b[i] {
-			return 1
+	}
+	return ids, nil
+}
+
+// numeric identifiers are compared numerically and have lower precedence
+// than alphanumeric ones, which are compared in ASCII order
+func comparePreReleaseIdentifiers(a, b string) int {
+	aNum, bNum := isASCIIDigits(a), isASCIIDigits(b)
+	switch {
+	case aNum && bNum:
+		// identifiers have no leading zeros so longer means larger
+		if c := compareInts(len(a), len(b)); c != 0 {
+			return c
+		}
+		return strings.Compare(a, b)
+	case aNum:
+		return -1
+	case bNum:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+func isASCIIDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
 		}
 	}
+	return true
+}
+
+func compareInts(a, b int) int {
+	if a < b {
+		return -1
+	}
+	if a > b {
+		return 1
+	}
 	return 0
 }
@@ -0,0 +1,97 @@
+package fstaskparser_test
+
+import (
+	"testing"
+
+	"github.com/programme-lv/fs-task-format-parser/pkg/fstaskparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSpecVersion(t *testing.T) {
+	v, err := fstaskparser.ParseSpecVersion("v2.5.0-rc.1+build.7")
+	require.NoErrorf(t, err, "failed to parse version: %v", err)
+	assert.Equal(t, 2, v.Major)
+	assert.Equal(t, 5, v.Minor)
+	assert.Equal(t, 0, v.Patch)
+	assert.Equal(t, []string{"rc", "1"}, v.PreRelease)
+	assert.Equal(t, []string{"build", "7"}, v.Build)
+	assert.Equal(t, "v2.5.0-rc.1+build.7", v.String())
+
+	// legacy versions without "v" and trailing parts
+	v, err = fstaskparser.ParseSpecVersion("2.2")
+	require.NoErrorf(t, err, "failed to parse version: %v", err)
+	assert.Equal(t, "v2.2.0", v.String())
+
+	invalid := []string{"", "v", "vv2.0.0", "2..0", "2.0.0.0", "02.0.0", "2.0.0-", "2.0.0-01", "2.0.0+", "2.0.0-a..b", "2.x.0", "2.0.0-ā"}
+	for _, s := range invalid {
+		_, err := fstaskparser.ParseSpecVersion(s)
+		assert.Errorf(t, err, "expected error parsing %q", s)
+	}
+}
+
+func TestSpecVersionCompare(t *testing.T) {
+	// ordered by increasing precedence as in the semver 2.0 specification
+	ordered := []string{
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"2.0.0",
+		"2.1.0",
+		"2.1.1",
+		"v2.5.0-rc.1",
+		"v2.5.0",
+	}
+
+	for i := 0; i < len(ordered); i++ {
+		for j := 0; j < len(ordered); j++ {
+			a := fstaskparser.MustParseSpecVersion(ordered[i])
+			b := fstaskparser.MustParseSpecVersion(ordered[j])
+			expected := 0
+			if i < j {
+				expected = -1
+			} else if i > j {
+				expected = 1
+			}
+			assert.Equalf(t, expected, a.Compare(b), "comparing %s and %s", ordered[i], ordered[j])
+		}
+	}
+
+	// build metadata does not affect precedence
+	a := fstaskparser.MustParseSpecVersion("v2.4.0+abc")
+	b := fstaskparser.MustParseSpecVersion("2.4")
+	assert.Equal(t, 0, a.Compare(b))
+	assert.True(t, a.AtLeast(b))
+}
+
+func FuzzParseSpecVersion(f *testing.F) {
+	seeds := []string{"", "v", "2.2", "v2.4.0", "v2.5.0-rc.1", "1.0.0-alpha+001", "0.0.0-0.a-b.c+x.y-z", "01.2.3"}
+	for _, s := range seeds {
+		f.Add(s)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		v, err := fstaskparser.ParseSpecVersion(s)
+		if err != nil {
+			return
+		}
+
+		// the canonical form must parse back to an equal version
+		formatted := v.String()
+		v2, err := fstaskparser.ParseSpecVersion(formatted)
+		if err != nil {
+			t.Fatalf("failed to reparse %q (from %q): %v", formatted, s, err)
+		}
+		if v.Compare(v2) != 0 || v2.Compare(v) != 0 {
+			t.Fatalf("reparsed %q differs from %q", formatted, s)
+		}
+		if v2.String() != formatted {
+			t.Fatalf("formatting is not stable: %q != %q", v2.String(), formatted)
+		}
+	})
+}
@@ -0,0 +1,114 @@
+package fstaskparser_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/programme-lv/fs-task-format-parser/pkg/fstaskparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEditingStatementsByLanguage(t *testing.T) {
+	parsedTask, err := fstaskparser.Read(testTaskPath)
+	require.NoErrorf(t, err, "failed to read task: %v", err)
+
+	lv, err := parsedTask.GetMarkdownStatement("lv")
+	require.NoErrorf(t, err, "failed to get statement: %v", err)
+	assert.Equal(t, "lv", *lv.Language)
+	_, err = parsedTask.GetMarkdownStatement("en")
+	require.Error(t, err)
+
+	err = parsedTask.SetMarkdownStatement("en-us", fstaskparser.MarkdownStatement{
+		Story:  "Story",
+		Input:  "Input",
+		Output: "Output",
+	})
+	require.NoErrorf(t, err, "failed to set statement: %v", err)
+	en, err := parsedTask.GetMarkdownStatement("en-US")
+	require.NoErrorf(t, err, "failed to get statement: %v", err)
+	assert.Equal(t, "Story", en.Story)
+
+	// lookups normalize the tag like the setters do
+	en, err = parsedTask.GetMarkdownStatement("en-us")
+	require.NoErrorf(t, err, "failed to get statement: %v", err)
+	assert.Equal(t, "en-US", *en.Language)
+	require.NoError(t, parsedTask.AddPDFStatement("EN-us", []byte("%PDF-en")))
+	pdf, err := parsedTask.GetPDFStatement("en-us")
+	require.NoErrorf(t, err, "failed to get pdf statement: %v", err)
+	assert.Equal(t, []byte("%PDF-en"), pdf)
+	require.NoError(t, parsedTask.RemovePDFStatement("en-us"))
+
+	// setting again replaces the statement
+	en.Story = "Another story"
+	err = parsedTask.SetMarkdownStatement("en-US", en)
+	require.NoErrorf(t, err, "failed to set statement: %v", err)
+	assert.Equal(t, 2, len(parsedTask.GetMarkdownStatements()))
+
+	err = parsedTask.SetMarkdownStatement("not a language", en)
+	require.Error(t, err)
+
+	require.NoError(t, parsedTask.RemoveStatement("lv"))
+	require.Error(t, parsedTask.RemoveStatement("lv"))
+	statements := parsedTask.GetMarkdownStatements()
+	require.Equal(t, 1, len(statements))
+	assert.Equal(t, "Another story", statements[0].Story)
+
+	require.Error(t, parsedTask.AddPDFStatement("lv", []byte("%PDF")))
+	require.NoError(t, parsedTask.ReplacePDFStatement("lv", []byte("%PDF")))
+	pdf, err = parsedTask.GetPDFStatement("lv")
+	require.NoErrorf(t, err, "failed to get pdf statement: %v", err)
+	assert.Equal(t, []byte("%PDF"), pdf)
+	require.Error(t, parsedTask.ReplacePDFStatement("??", []byte("%PDF")))
+	require.NoError(t, parsedTask.RemovePDFStatement("lv"))
+	require.Error(t, parsedTask.RemovePDFStatement("lv"))
+	assert.Empty(t, parsedTask.GetAllPDFStatements())
+
+	tmpDirectory, err := os.MkdirTemp("", "fstaskparser-test-")
+	require.NoErrorf(t, err, "failed to create temporary directory: %v", err)
+	defer os.RemoveAll(tmpDirectory)
+
+	outputDirectory := filepath.Join(tmpDirectory, "kvadrputekl")
+	err = parsedTask.Store(outputDirectory)
+	require.NoErrorf(t, err, "failed to store task: %v", err)
+
+	storedTask, err := fstaskparser.Read(outputDirectory)
+	require.NoErrorf(t, err, "failed to read task: %v", err)
+	assert.Empty(t, storedTask.GetReadDiagnostics())
+	en, err = storedTask.GetMarkdownStatement("en-US")
+	require.NoErrorf(t, err, "failed to get statement: %v", err)
+	assert.Equal(t, "Another story", en.Story)
+	_, err = storedTask.GetMarkdownStatement("lv")
+	require.Error(t, err)
+
+	// directories named by non-canonical tags are read normalized
+	err = os.Rename(filepath.Join(outputDirectory, "statements", "md", "en-US"),
+		filepath.Join(outputDirectory, "statements", "md", "en-us"))
+	require.NoErrorf(t, err, "failed to rename directory: %v", err)
+	err = os.MkdirAll(filepath.Join(outputDirectory, "statements", "pdf"), 0755)
+	require.NoErrorf(t, err, "failed to create directory: %v", err)
+	err = os.WriteFile(filepath.Join(outputDirectory, "statements", "pdf", "EN-us.pdf"), []byte("%PDF"), 0644)
+	require.NoErrorf(t, err, "failed to write pdf: %v", err)
+	storedTask, err = fstaskparser.Read(outputDirectory)
+	require.NoErrorf(t, err, "failed to read task: %v", err)
+	assert.Empty(t, storedTask.GetReadDiagnostics())
+	pdf, err = storedTask.GetPDFStatement("en-us")
+	require.NoErrorf(t, err, "failed to get pdf statement: %v", err)
+	assert.Equal(t, []byte("%PDF"), pdf)
+	for _, lang := range []string{"en-us", "en-US"} {
+		en, err = storedTask.GetMarkdownStatement(lang)
+		require.NoErrorf(t, err, "failed to get statement: %v", err)
+		assert.Equal(t, "Another story", en.Story)
+	}
+
+	// statement directories must be named by language tags
+	err = os.Rename(filepath.Join(outputDirectory, "statements", "md", "en-us"),
+		filepath.Join(outputDirectory, "statements", "md", "english_"))
+	require.NoErrorf(t, err, "failed to rename directory: %v", err)
+	storedTask, err = fstaskparser.Read(outputDirectory)
+	require.NoErrorf(t, err, "failed to read task: %v", err)
+	diagnostics := storedTask.GetReadDiagnostics()
+	require.Equal(t, 1, len(diagnostics))
+	assert.Equal(t, "statements/md/english_", diagnostics[0].Path)
+}
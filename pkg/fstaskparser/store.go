@@ -8,9 +8,38 @@ import (
 	"sort"
 )
 
-const proglvFSTaskFormatSpecVersOfScript = "v2.4.0"
+const proglvFSTaskFormatSpecVersOfScript = "v2.5.0"
+
+var proglvFSTaskFormatSpecVersion = MustParseSpecVersion(proglvFSTaskFormatSpecVersOfScript)
+
+// versions at which problem.toml fields were introduced
+var (
+	specVersion2_2 = MustParseSpecVersion("v2.2.0")
+	specVersion2_3 = MustParseSpecVersion("v2.3.0")
+	specVersion2_4 = MustParseSpecVersion("v2.4.0")
+	specVersion2_5 = MustParseSpecVersion("v2.5.0")
+)
+
+// AnswerExtension selects the extension of stored test answer and example output files.
+type AnswerExtension string
+
+const (
+	// AnswerExtKeep keeps the file naming tests were read with, new tests get ".in" and ".out"
+	AnswerExtKeep AnswerExtension = ""
+	AnswerExtOut  AnswerExtension = ".out"
+	AnswerExtAns  AnswerExtension = ".ans"
+)
+
+// StoreOptions control how a task is written to a directory.
+type StoreOptions struct {
+	AnswerExtension AnswerExtension
+}
 
 func (task *Task) Store(dirPath string) error {
+	return task.StoreWithOptions(dirPath, StoreOptions{})
+}
+
+func (task *Task) StoreWithOptions(dirPath string, opts StoreOptions) error {
 	log.Printf("Starting to store task to directory: %s\n", dirPath)
 	if _, err := os.Stat(dirPath); !os.IsNotExist(err) {
 		log.Printf("Directory already exists: %s\n", dirPath)
@@ -30,14 +59,14 @@ func (task *Task) Store(dirPath string) error {
 	}
 	log.Println("problem.toml written successfully")
 
-	err = task.storeTests(filepath.Join(dirPath, "tests"))
+	err = task.storeTests(filepath.Join(dirPath, "tests"), opts)
 	if err != nil {
 		log.Printf("Error storing tests: %v\n", err)
 		return fmt.Errorf("error storing tests: %w", err)
 	}
 	log.Println("tests written successfully")
 
-	err = task.storeExamples(filepath.Join(dirPath, "examples"))
+	err = task.storeExamples(filepath.Join(dirPath, "examples"), opts)
 	if err != nil {
 		log.Printf("Error storing examples: %v\n", err)
 		return fmt.Errorf("error storing examples: %w", err)
@@ -65,11 +94,38 @@ func (task *Task) Store(dirPath string) error {
 	}
 	log.Println("assets written successfully")
 
+	if task.checker != nil {
+		err = os.MkdirAll(filepath.Join(dirPath, "evaluation"), 0755)
+		if err != nil {
+			log.Printf("Error creating evaluation directory: %v\n", err)
+			return fmt.Errorf("error creating evaluation directory: %w", err)
+		}
+		err = os.WriteFile(filepath.Join(dirPath, "evaluation", "checker.cpp"), task.checker, 0644)
+		if err != nil {
+			log.Printf("Error writing checker: %v\n", err)
+			return fmt.Errorf("error writing checker: %w", err)
+		}
+		log.Println("checker written successfully")
+	}
+
+	if len(task.editorials) > 0 {
+		err = task.storeEditorials(filepath.Join(dirPath, "editorial"))
+		if err != nil {
+			log.Printf("Error storing editorials: %v\n", err)
+			return fmt.Errorf("error storing editorials: %w", err)
+		}
+		log.Println("editorials written successfully")
+	}
+
 	log.Printf("Task successfully stored in directory: %s\n", dirPath)
 	return nil
 }
 
 func (task *Task) storeAssets(assetDir string) error {
+	return storeAssetFiles(assetDir, task.assets)
+}
+
+func storeAssetFiles(assetDir string, assets []asset) error {
 	err := os.MkdirAll(assetDir, 0755)
 	if err != nil {
 		log.Printf("Error creating assets directory: %v\n", err)
@@ -77,10 +133,15 @@ func (task *Task) storeAssets(assetDir string) error {
 	}
 	log.Println("Assets directory created successfully")
 
-	for _, v := range task.assets {
+	for _, v := range assets {
 		// v.Content
 		// v.RelativePath
-		path := filepath.Join(assetDir, v.RelativePath)
+		path := filepath.Join(assetDir, filepath.FromSlash(v.RelativePath))
+		err = os.MkdirAll(filepath.Dir(path), 0755)
+		if err != nil {
+			log.Printf("Error creating asset directory: %v\n", err)
+			return fmt.Errorf("error creating asset directory: %w", err)
+		}
 		err = os.WriteFile(path, v.Content, 0644)
 		if err != nil {
 			log.Printf("Error writing asset: %v\n", err)
@@ -159,6 +220,22 @@ func (task *Task) storeMdStatements(mdStatementDir string) error {
 			}
 			log.Printf("Markdown statement written to: %s\n", notesPath)
 		}
+
+		for _, name := range sortedSectionNames(v.Sections) {
+			// the name becomes a file name, it must not replace story.md or leave the directory
+			err = validateStatementSectionName(name)
+			if err != nil {
+				log.Printf("Error writing Markdown statement: %v\n", err)
+				return fmt.Errorf("error writing Markdown statement: %w", err)
+			}
+			sectionPath := filepath.Join(dirPath, name+".md")
+			err = os.WriteFile(sectionPath, []byte(v.Sections[name]), 0644)
+			if err != nil {
+				log.Printf("Error writing Markdown statement: %v\n", err)
+				return fmt.Errorf("error writing Markdown statement: %w", err)
+			}
+			log.Printf("Markdown statement written to: %s\n", sectionPath)
+		}
 	}
 
 	return nil
@@ -202,7 +279,7 @@ func (task *Task) storeProblemToml(problemTomlPath string) error {
 	return nil
 }
 
-func (task *Task) storeTests(testsDirPath string) error {
+func (task *Task) storeTests(testsDirPath string, opts StoreOptions) error {
 	var err error
 	err = os.Mkdir(testsDirPath, 0755)
 	if err != nil {
@@ -213,8 +290,16 @@ func (task *Task) storeTests(testsDirPath string) error {
 
 	for _, t := range task.tests {
 		fname := task.getTestToBeWrittenFname(t.ID)
-		inPath := filepath.Join(testsDirPath, fname+".in")
-		ansPath := filepath.Join(testsDirPath, fname+".out")
+		pattern := opts.testFilePattern(task.testFilePatterns[fname])
+		inPath := filepath.Join(testsDirPath, filepath.FromSlash(testFilePath(pattern.Input, fname)))
+		ansPath := filepath.Join(testsDirPath, filepath.FromSlash(testFilePath(pattern.Answer, fname)))
+
+		// tests in nested directories have them in their filename
+		err = os.MkdirAll(filepath.Dir(inPath), 0755)
+		if err != nil {
+			log.Printf("Error creating test directory %s: %v\n", filepath.Dir(inPath), err)
+			return fmt.Errorf("error creating test directory: %w", err)
+		}
 
 		err = os.WriteFile(inPath, t.Input, 0644)
 		if err != nil {
@@ -272,7 +357,7 @@ func (task *Task) getTestIDByFilenameOverwriteMap() map[string]int {
 	return res
 }
 
-func (task *Task) storeExamples(examplesDirPath string) error {
+func (task *Task) storeExamples(examplesDirPath string, opts StoreOptions) error {
 	var err error
 	err = os.Mkdir(examplesDirPath, 0755)
 	if err != nil {
@@ -285,13 +370,15 @@ func (task *Task) storeExamples(examplesDirPath string) error {
 		var ansPath string
 		var mdPath string
 
+		inExt := orDefault(e.InputExt, ".in")
+		ansExt := opts.answerExt(e.OutputExt)
 		if e.Name != nil {
-			inPath = filepath.Join(examplesDirPath, *e.Name+".in")
-			ansPath = filepath.Join(examplesDirPath, *e.Name+".out")
+			inPath = filepath.Join(examplesDirPath, *e.Name+inExt)
+			ansPath = filepath.Join(examplesDirPath, *e.Name+ansExt)
 			mdPath = filepath.Join(examplesDirPath, *e.Name+".md")
 		} else {
-			inName := fmt.Sprintf("%03d.in", i+1)
-			ansName := fmt.Sprintf("%03d.out", i+1)
+			inName := fmt.Sprintf("%03d%s", i+1, inExt)
+			ansName := fmt.Sprintf("%03d%s", i+1, ansExt)
 			mdName := fmt.Sprintf("%03d.md", i+1)
 			inPath = filepath.Join(examplesDirPath, inName)
 			ansPath = filepath.Join(examplesDirPath, ansName)
@@ -321,3 +408,30 @@ func (task *Task) storeExamples(examplesDirPath string) error {
 	log.Println("Example files written successfully")
 	return nil
 }
+
+// testFilePattern returns the naming to store a test with given the one it
+// was read with. Choosing an answer extension also normalises the input to ".in".
+func (opts StoreOptions) testFilePattern(original TestFilePattern) TestFilePattern {
+	if opts.AnswerExtension != AnswerExtKeep {
+		return TestFilePattern{Input: "*.in", Answer: "*" + string(opts.AnswerExtension)}
+	}
+	if original.Input == "" || original.Answer == "" {
+		return defaultTestFilePattern
+	}
+	return original
+}
+
+// answerExt returns the extension to store an answer with given the one it was read with
+func (opts StoreOptions) answerExt(original string) string {
+	if opts.AnswerExtension != AnswerExtKeep {
+		return string(opts.AnswerExtension)
+	}
+	return orDefault(original, ".out")
+}
+
+func orDefault(s string, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
@@ -0,0 +1,58 @@
+package fstaskparser_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/programme-lv/fs-task-format-parser/pkg/fstaskparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreservingAnswerExtensions(t *testing.T) {
+	parsedTask, err := fstaskparser.Read(testTaskPath)
+	require.NoErrorf(t, err, "failed to read task: %v", err)
+
+	tmpDirectory, err := os.MkdirTemp("", "fstaskparser-test-")
+	require.NoErrorf(t, err, "failed to create temporary directory: %v", err)
+	defer os.RemoveAll(tmpDirectory)
+
+	ansDirectory := filepath.Join(tmpDirectory, "ans")
+	err = parsedTask.StoreWithOptions(ansDirectory, fstaskparser.StoreOptions{
+		AnswerExtension: fstaskparser.AnswerExtAns,
+	})
+	require.NoErrorf(t, err, "failed to store task: %v", err)
+	assert.FileExists(t, filepath.Join(ansDirectory, "tests", "kp01a.ans"))
+	assert.NoFileExists(t, filepath.Join(ansDirectory, "tests", "kp01a.out"))
+	assert.FileExists(t, filepath.Join(ansDirectory, "examples", "kp00.ans"))
+
+	// a read-store round-trip keeps the original extension
+	ansTask, err := fstaskparser.Read(ansDirectory)
+	require.NoErrorf(t, err, "failed to read task: %v", err)
+
+	keptDirectory := filepath.Join(tmpDirectory, "kept")
+	err = ansTask.Store(keptDirectory)
+	require.NoErrorf(t, err, "failed to store task: %v", err)
+	assert.FileExists(t, filepath.Join(keptDirectory, "tests", "kp02c.ans"))
+	assert.FileExists(t, filepath.Join(keptDirectory, "tests", "kp02c.in"))
+	assert.FileExists(t, filepath.Join(keptDirectory, "examples", "kp01.ans"))
+
+	keptTask, err := fstaskparser.Read(keptDirectory)
+	require.NoErrorf(t, err, "failed to read task: %v", err)
+	assert.Equal(t, parsedTask.GetTestsSortedByID(), keptTask.GetTestsSortedByID())
+
+	// tests added later get the default extension
+	id := keptTask.AddTest([]byte("1 1 1\n"), []byte("0\n"))
+	keptTask.AssignFilenameToTest("kp03a", id)
+
+	outDirectory := filepath.Join(tmpDirectory, "out")
+	err = keptTask.StoreWithOptions(outDirectory, fstaskparser.StoreOptions{
+		AnswerExtension: fstaskparser.AnswerExtOut,
+	})
+	require.NoErrorf(t, err, "failed to store task: %v", err)
+	assert.FileExists(t, filepath.Join(outDirectory, "tests", "kp01a.out"))
+	assert.FileExists(t, filepath.Join(outDirectory, "tests", "kp03a.out"))
+	assert.NoFileExists(t, filepath.Join(outDirectory, "tests", "kp01a.ans"))
+	assert.FileExists(t, filepath.Join(outDirectory, "examples", "kp00.out"))
+}
@@ -0,0 +1,128 @@
+package fstaskparser
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// UnknownKey is a problem.toml key that has no corresponding field in ProblemTOML.
+type UnknownKey struct {
+	Key        string // dotted key path, e.g. "constraints.memory_megabyte"
+	Line       int
+	Column     int
+	Suggestion string // closest known key at the same level, empty if none is close
+}
+
+func (k UnknownKey) String() string {
+	res := fmt.Sprintf("%d:%d: unknown key %q", k.Line, k.Column, k.Key)
+	if k.Suggestion != "" {
+		res += fmt.Sprintf(" (did you mean %q?)", k.Suggestion)
+	}
+	return res
+}
+
+// UnknownKeysError is returned by strict reading when problem.toml
+// contains keys that are not part of the specification.
+type UnknownKeysError struct {
+	Keys []UnknownKey
+}
+
+func (e *UnknownKeysError) Error() string {
+	msgs := make([]string, 0, len(e.Keys))
+	for _, k := range e.Keys {
+		msgs = append(msgs, k.String())
+	}
+	return fmt.Sprintf("unknown keys in problem.toml: %s", strings.Join(msgs, "; "))
+}
+
+// newUnknownKeysError converts go-toml's strict mode error into an
+// *UnknownKeysError, suggesting keys known to the schema struct typ.
+func newUnknownKeysError(strictErr *toml.StrictMissingError, typ reflect.Type) *UnknownKeysError {
+	known := make(map[string][]string)
+	collectTomlKeys(typ, "", known)
+
+	res := &UnknownKeysError{Keys: make([]UnknownKey, 0, len(strictErr.Errors))}
+	for _, decErr := range strictErr.Errors {
+		key := decErr.Key()
+		line, col := decErr.Position()
+		parent := strings.Join(key[:len(key)-1], ".")
+		res.Keys = append(res.Keys, UnknownKey{
+			Key:        strings.Join(key, "."),
+			Line:       line,
+			Column:     col,
+			Suggestion: closestKey(key[len(key)-1], known[parent], parent),
+		})
+	}
+
+	log.Printf("Found unknown keys: %v\n", res)
+	return res
+}
+
+// collectTomlKeys maps a dotted table path ("" for the root table)
+// to the keys the struct type accepts in that table.
+func collectTomlKeys(typ reflect.Type, prefix string, res map[string][]string) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		name := strings.Split(field.Tag.Get("toml"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		res[prefix] = append(res[prefix], name)
+
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Slice {
+			fieldType = fieldType.Elem()
+		}
+		if fieldType.Kind() == reflect.Struct {
+			collectTomlKeys(fieldType, path, res)
+		}
+	}
+}
+
+// closestKey returns the candidate with the smallest edit distance to key
+// if it is close enough to be a plausible typo.
+func closestKey(key string, candidates []string, prefix string) string {
+	best := ""
+	bestDist := len(key)/2 + 1
+	for _, c := range candidates {
+		d := levenshteinDistance(key, c)
+		if d < bestDist {
+			best = c
+			bestDist = d
+		}
+	}
+	if best != "" && prefix != "" {
+		return prefix + "." + best
+	}
+	return best
+}
+
+func levenshteinDistance(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	prev := make([]int, len(br)+1)
+	curr := make([]int, len(br)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		curr[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(br)]
+}
@@ -0,0 +1,57 @@
+package fstaskparser_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/programme-lv/fs-task-format-parser/pkg/fstaskparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStrictReadingRejectsUnknownKeys(t *testing.T) {
+	_, err := fstaskparser.ReadWithOptions(testTaskPath, fstaskparser.ReadOptions{Strict: true})
+	require.NoErrorf(t, err, "failed to read task in strict mode: %v", err)
+
+	parsedTask, err := fstaskparser.Read(testTaskPath)
+	require.NoErrorf(t, err, "failed to read task: %v", err)
+
+	tmpDirectory, err := os.MkdirTemp("", "fstaskparser-test-")
+	require.NoErrorf(t, err, "failed to create temporary directory: %v", err)
+	defer os.RemoveAll(tmpDirectory)
+
+	outputDirectory := filepath.Join(tmpDirectory, "kvadrputekl")
+	err = parsedTask.Store(outputDirectory)
+	require.NoErrorf(t, err, "failed to store task: %v", err)
+
+	pTomlPath := filepath.Join(outputDirectory, "problem.toml")
+	pToml, err := os.ReadFile(pTomlPath)
+	require.NoErrorf(t, err, "failed to read problem.toml: %v", err)
+
+	typos := strings.Replace(string(pToml), "memory_megabytes", "memory_megabyte", 1)
+	typos = strings.Replace(typos, "public = false", "publc = false", 1)
+	err = os.WriteFile(pTomlPath, []byte(typos), 0644)
+	require.NoErrorf(t, err, "failed to write problem.toml: %v", err)
+
+	// non-strict reading ignores the typos
+	_, err = fstaskparser.Read(outputDirectory)
+	require.NoErrorf(t, err, "failed to read task: %v", err)
+
+	_, err = fstaskparser.ReadWithOptions(outputDirectory, fstaskparser.ReadOptions{Strict: true})
+	require.Error(t, err)
+
+	var unknownKeysErr *fstaskparser.UnknownKeysError
+	require.True(t, errors.As(err, &unknownKeysErr))
+	require.Equal(t, 2, len(unknownKeysErr.Keys))
+
+	assert.Equal(t, "constraints.memory_megabyte", unknownKeysErr.Keys[0].Key)
+	assert.Equal(t, "constraints.memory_megabytes", unknownKeysErr.Keys[0].Suggestion)
+	assert.Greater(t, unknownKeysErr.Keys[0].Line, 0)
+
+	assert.Equal(t, "test_groups.publc", unknownKeysErr.Keys[1].Key)
+	assert.Equal(t, "test_groups.public", unknownKeysErr.Keys[1].Suggestion)
+	assert.Greater(t, unknownKeysErr.Keys[1].Line, unknownKeysErr.Keys[0].Line)
+}
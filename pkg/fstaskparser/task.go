@@ -34,6 +34,7 @@ type Task struct {
 	testFilenameToID map[string]int
 	testIDOverwrite  map[string]int // used only during reading directory
 	testIDToFilename map[int]string
+	testFilePatterns map[string]TestFilePattern // file naming each test was read with
 	tests            []test
 
 	/*
@@ -56,10 +57,19 @@ type Task struct {
 
 	illstrImgFname string
 
+	checker []byte // evaluation/checker.cpp, nil if answers are compared token by token
+
 	assets []asset
 
 	OriginNotes       map[string]string
 	OriginInstitution string
+
+	statementSectionVisibility map[string]SectionVisibility // declared in problem.toml
+
+	editorials       map[string]editorial // map language to editorial
+	editorialRelease EditorialRelease
+
+	readDiagnostics []ReadDiagnostic
 }
 
 type asset struct {
@@ -74,6 +84,7 @@ type mDStatement struct {
 	Output   string
 	Notes    *string
 	Scoring  *string
+	Sections map[string]string // additional sections by name, e.g. "interaction"
 }
 
 // tests are executed in order of ID
@@ -94,6 +105,9 @@ type example struct {
 	Output []byte
 	MdNote []byte
 	Name   *string
+
+	InputExt  string // original input file extension, empty for new examples
+	OutputExt string // original output file extension, empty for new examples
 }
 
 func NewTask(taskName string) (*Task, error) {
@@ -114,6 +128,7 @@ func NewTask(taskName string) (*Task, error) {
 		testFilenameToID:     map[string]int{},
 		testIDOverwrite:      map[string]int{},
 		testIDToFilename:     map[int]string{},
+		testFilePatterns:     map[string]TestFilePattern{},
 		tests:                []test{},
 		testGroupIDs:         []int{},
 		isTGroupPublic:       map[int]bool{},
@@ -125,6 +140,10 @@ func NewTask(taskName string) (*Task, error) {
 		assets:               []asset{},
 		OriginNotes:          map[string]string{},
 		OriginInstitution:    "",
+
+		statementSectionVisibility: map[string]SectionVisibility{},
+		editorials:                 map[string]editorial{},
+		editorialRelease:           EditorialReleaseAfterContest,
 	}
 
 	return &t, nil
@@ -0,0 +1,493 @@
+package fstaskparser
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// JSONContentMode tells how file contents are written in TaskJSON.
+type JSONContentMode int
+
+const (
+	// JSONContentInline writes contents as base64 next to their hash
+	JSONContentInline JSONContentMode = iota
+	// JSONContentHash writes only the SHA-256 hash and size of contents,
+	// they are looked up with a BlobResolver when the task is restored
+	JSONContentHash
+)
+
+// JSONOptions control Task.ToJSON.
+type JSONOptions struct {
+	Contents JSONContentMode
+}
+
+// BlobResolver returns the content with the given hex encoded SHA-256 hash.
+type BlobResolver func(sha256 string) ([]byte, error)
+
+// JSONBlob is the content of a file, Base64 is left out in JSONContentHash mode.
+type JSONBlob struct {
+	SHA256 string `json:"sha256"`
+	Size   int    `json:"size"`
+	Base64 []byte `json:"base64,omitempty"`
+}
+
+// TaskJSON is the JSON representation of the whole task model.
+// Slices and maps keep the distinction between null and empty so that
+// a task survives ToJSON and TaskFromJSON unchanged.
+type TaskJSON struct {
+	Name                 string            `json:"name"`
+	Tags                 []string          `json:"tags"`
+	Authors              []string          `json:"authors"`
+	OriginOlympiad       string            `json:"origin_olympiad"`
+	OriginInstitution    string            `json:"origin_institution"`
+	OriginNotes          map[string]string `json:"origin_notes"`
+	DifficultyOneToFive  int               `json:"difficulty_one_to_five"`
+	CPUTimeSeconds       float64           `json:"cpu_time_seconds"`
+	MemoryMegabytes      int               `json:"memory_megabytes"`
+	VisibleInputSubtasks []int             `json:"visible_input_subtasks"`
+
+	Tests            []TestJSON                 `json:"tests"`
+	TestGroups       []TestGroupJSON            `json:"test_groups"`
+	TestFilePatterns map[string]TestFilePattern `json:"test_file_patterns,omitempty"`
+	Examples         []ExampleJSON              `json:"examples"`
+
+	MarkdownStatements []MarkdownStatementJSON      `json:"markdown_statements"`
+	PDFStatements      map[string]JSONBlob          `json:"pdf_statements"`
+	StatementSections  map[string]SectionVisibility `json:"statement_sections"`
+	Assets             []AssetJSON                  `json:"assets"`
+	IllustrationImage  string                       `json:"illustration_image,omitempty"`
+
+	Checker          *JSONBlob                `json:"checker,omitempty"`
+	Editorials       map[string]EditorialJSON `json:"editorials"`
+	EditorialRelease EditorialRelease         `json:"editorial_release"`
+}
+
+type TestJSON struct {
+	ID       int      `json:"id"`
+	Filename string   `json:"filename,omitempty"` // empty if the test has no assigned filename
+	Input    JSONBlob `json:"input"`
+	Answer   JSONBlob `json:"answer"`
+}
+
+type TestGroupJSON struct {
+	ID      int   `json:"id"`
+	Points  int   `json:"points"`
+	Public  bool  `json:"public"`
+	Subtask int   `json:"subtask"`
+	TestIDs []int `json:"test_ids"`
+}
+
+type ExampleJSON struct {
+	Name      *string   `json:"name,omitempty"`
+	Input     JSONBlob  `json:"input"`
+	Output    JSONBlob  `json:"output"`
+	MdNote    *JSONBlob `json:"md_note,omitempty"`
+	InputExt  string    `json:"input_ext,omitempty"`
+	OutputExt string    `json:"output_ext,omitempty"`
+}
+
+type MarkdownStatementJSON struct {
+	Language *string           `json:"language"`
+	Story    string            `json:"story"`
+	Input    string            `json:"input"`
+	Output   string            `json:"output"`
+	Notes    *string           `json:"notes,omitempty"`
+	Scoring  *string           `json:"scoring,omitempty"`
+	Sections map[string]string `json:"sections,omitempty"`
+}
+
+type AssetJSON struct {
+	RelativePath string   `json:"path"`
+	Content      JSONBlob `json:"content"`
+}
+
+type EditorialJSON struct {
+	Markdown *string     `json:"markdown,omitempty"`
+	PDF      *JSONBlob   `json:"pdf,omitempty"`
+	Assets   []AssetJSON `json:"assets"`
+}
+
+func newJSONBlob(content []byte, opts JSONOptions) JSONBlob {
+	sum := sha256.Sum256(content)
+	b := JSONBlob{SHA256: hex.EncodeToString(sum[:]), Size: len(content)}
+	if opts.Contents == JSONContentInline {
+		b.Base64 = bytes.Clone(content)
+	}
+	return b
+}
+
+func newJSONBlobPtr(content []byte, opts JSONOptions) *JSONBlob {
+	if content == nil {
+		return nil
+	}
+	b := newJSONBlob(content, opts)
+	return &b
+}
+
+func newAssetsJSON(assets []asset, opts JSONOptions) []AssetJSON {
+	if assets == nil {
+		return nil
+	}
+	res := make([]AssetJSON, 0, len(assets))
+	for _, a := range assets {
+		res = append(res, AssetJSON{RelativePath: a.RelativePath, Content: newJSONBlob(a.Content, opts)})
+	}
+	return res
+}
+
+// ToJSON returns the JSON representation of the task
+func (t *Task) ToJSON(opts JSONOptions) TaskJSON {
+	res := TaskJSON{
+		Name:                 t.taskName,
+		Tags:                 slices.Clone(t.problemTags),
+		Authors:              slices.Clone(t.problemAuthors),
+		OriginOlympiad:       t.originOlympiad,
+		OriginInstitution:    t.OriginInstitution,
+		OriginNotes:          maps.Clone(t.OriginNotes),
+		DifficultyOneToFive:  t.difficultyOneToFive,
+		CPUTimeSeconds:       t.cpuTimeSeconds,
+		MemoryMegabytes:      t.memoryMegabytes,
+		VisibleInputSubtasks: slices.Clone(t.visibleInputSubtasks),
+		TestFilePatterns:     maps.Clone(t.testFilePatterns),
+		StatementSections:    maps.Clone(t.statementSectionVisibility),
+		Assets:               newAssetsJSON(t.assets, opts),
+		IllustrationImage:    t.illstrImgFname,
+		Checker:              newJSONBlobPtr(t.checker, opts),
+		EditorialRelease:     t.editorialRelease,
+	}
+
+	res.Tests = make([]TestJSON, 0, len(t.tests))
+	for _, test := range t.tests {
+		res.Tests = append(res.Tests, TestJSON{
+			ID:       test.ID,
+			Filename: t.testIDToFilename[test.ID],
+			Input:    newJSONBlob(test.Input, opts),
+			Answer:   newJSONBlob(test.Answer, opts),
+		})
+	}
+
+	res.TestGroups = make([]TestGroupJSON, 0, len(t.testGroupIDs))
+	for _, id := range t.testGroupIDs {
+		res.TestGroups = append(res.TestGroups, TestGroupJSON{
+			ID:      id,
+			Points:  t.tGroupPoints[id],
+			Public:  t.isTGroupPublic[id],
+			Subtask: t.tGroupToStMap[id],
+			TestIDs: slices.Clone(t.tGroupTestIDs[id]),
+		})
+	}
+
+	res.Examples = make([]ExampleJSON, 0, len(t.examples))
+	for _, e := range t.examples {
+		res.Examples = append(res.Examples, ExampleJSON{
+			Name:      clonePtr(e.Name),
+			Input:     newJSONBlob(e.Input, opts),
+			Output:    newJSONBlob(e.Output, opts),
+			MdNote:    newJSONBlobPtr(e.MdNote, opts),
+			InputExt:  e.InputExt,
+			OutputExt: e.OutputExt,
+		})
+	}
+
+	res.MarkdownStatements = make([]MarkdownStatementJSON, 0, len(t.mdStatements))
+	for _, st := range t.mdStatements {
+		res.MarkdownStatements = append(res.MarkdownStatements, MarkdownStatementJSON(st.clone()))
+	}
+
+	res.PDFStatements = make(map[string]JSONBlob, len(t.pdfStatements))
+	for lang, pdf := range t.pdfStatements {
+		res.PDFStatements[lang] = newJSONBlob(pdf, opts)
+	}
+
+	res.Editorials = make(map[string]EditorialJSON, len(t.editorials))
+	for lang, e := range t.editorials {
+		res.Editorials[lang] = EditorialJSON{
+			Markdown: clonePtr(e.Markdown),
+			PDF:      newJSONBlobPtr(e.PDF, opts),
+			Assets:   newAssetsJSON(e.Assets, opts),
+		}
+	}
+
+	return res
+}
+
+// blobDecoder restores blob contents, checking them against their hash
+type blobDecoder struct {
+	resolve BlobResolver
+}
+
+func (d blobDecoder) decode(b JSONBlob) ([]byte, error) {
+	content := bytes.Clone(b.Base64)
+	if content == nil && b.Size > 0 {
+		if d.resolve == nil {
+			return nil, fmt.Errorf("content %s is not included and there is no resolver", b.SHA256)
+		}
+		var err error
+		content, err = d.resolve(b.SHA256)
+		if err != nil {
+			return nil, fmt.Errorf("error resolving content %s: %w", b.SHA256, err)
+		}
+	}
+	if content == nil {
+		content = []byte{}
+	}
+
+	sum := sha256.Sum256(content)
+	if hex.EncodeToString(sum[:]) != b.SHA256 || len(content) != b.Size {
+		return nil, fmt.Errorf("content does not match its hash %s", b.SHA256)
+	}
+	return content, nil
+}
+
+func (d blobDecoder) decodePtr(b *JSONBlob) ([]byte, error) {
+	if b == nil {
+		return nil, nil
+	}
+	return d.decode(*b)
+}
+
+func (d blobDecoder) decodeAssets(assets []AssetJSON) ([]asset, error) {
+	if assets == nil {
+		return nil, nil
+	}
+	res := make([]asset, 0, len(assets))
+	for _, a := range assets {
+		if err := validateRelativePath(a.RelativePath); err != nil {
+			return nil, fmt.Errorf("asset: %w", err)
+		}
+		content, err := d.decode(a.Content)
+		if err != nil {
+			return nil, fmt.Errorf("asset %s: %w", a.RelativePath, err)
+		}
+		res = append(res, asset{RelativePath: a.RelativePath, Content: content})
+	}
+	return res, nil
+}
+
+// TaskFromJSON restores a task from its JSON representation. Contents that
+// are given only by their hash are looked up with resolve, which may be nil
+// when all contents are inline.
+func TaskFromJSON(tj TaskJSON, resolve BlobResolver) (*Task, error) {
+	d := blobDecoder{resolve: resolve}
+	t, err := NewTask(tj.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	t.problemTags = slices.Clone(tj.Tags)
+	t.problemAuthors = slices.Clone(tj.Authors)
+	t.originOlympiad = tj.OriginOlympiad
+	t.OriginInstitution = tj.OriginInstitution
+	t.OriginNotes = maps.Clone(tj.OriginNotes)
+	// 0 is a task without difficulty, as written by NewTask
+	if tj.DifficultyOneToFive < 0 || tj.DifficultyOneToFive > 5 {
+		return nil, fmt.Errorf("difficulty %d is not from 1 to 5", tj.DifficultyOneToFive)
+	}
+	t.difficultyOneToFive = tj.DifficultyOneToFive
+	t.cpuTimeSeconds = tj.CPUTimeSeconds
+	t.memoryMegabytes = tj.MemoryMegabytes
+	t.visibleInputSubtasks = slices.Clone(tj.VisibleInputSubtasks)
+	for fname, p := range tj.TestFilePatterns {
+		err = validateTestFilePatterns([]TestFilePattern{p})
+		if err == nil {
+			err = validateRelativePath(testFilePath(p.Input, fname))
+		}
+		if err == nil {
+			err = validateRelativePath(testFilePath(p.Answer, fname))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("test %s file pattern: %w", fname, err)
+		}
+	}
+	if tj.TestFilePatterns != nil {
+		t.testFilePatterns = maps.Clone(tj.TestFilePatterns)
+	}
+	for name, v := range tj.StatementSections {
+		err = t.SetStatementSectionVisibility(name, v)
+		if err != nil {
+			return nil, err
+		}
+	}
+	t.illstrImgFname = tj.IllustrationImage
+	if tj.EditorialRelease != "" {
+		err = validateEditorialRelease(tj.EditorialRelease)
+		if err != nil {
+			return nil, err
+		}
+		t.editorialRelease = tj.EditorialRelease
+	}
+
+	t.assets, err = d.decodeAssets(tj.Assets)
+	if err != nil {
+		return nil, err
+	}
+	t.checker, err = d.decodePtr(tj.Checker)
+	if err != nil {
+		return nil, fmt.Errorf("checker: %w", err)
+	}
+
+	testIDs := make(map[int]bool, len(tj.Tests))
+	for _, tt := range tj.Tests {
+		if testIDs[tt.ID] {
+			return nil, fmt.Errorf("duplicate test ID %d", tt.ID)
+		}
+		testIDs[tt.ID] = true
+		input, err := d.decode(tt.Input)
+		if err != nil {
+			return nil, fmt.Errorf("test %d input: %w", tt.ID, err)
+		}
+		answer, err := d.decode(tt.Answer)
+		if err != nil {
+			return nil, fmt.Errorf("test %d answer: %w", tt.ID, err)
+		}
+		t.tests = append(t.tests, test{ID: tt.ID, Input: input, Answer: answer})
+		if tt.Filename != "" {
+			if err := validateRelativePath(tt.Filename); err != nil {
+				return nil, fmt.Errorf("test %d filename: %w", tt.ID, err)
+			}
+			if _, ok := t.testFilenameToID[tt.Filename]; ok {
+				return nil, fmt.Errorf("duplicate test filename %s", tt.Filename)
+			}
+			t.AssignFilenameToTest(tt.Filename, tt.ID)
+		}
+	}
+
+	groupedTestIDs := make(map[int]bool)
+	for _, g := range tj.TestGroups {
+		for _, id := range g.TestIDs {
+			if !testIDs[id] {
+				return nil, fmt.Errorf("test group %d references unknown test ID %d", g.ID, id)
+			}
+			if groupedTestIDs[id] {
+				return nil, fmt.Errorf("duplicate test ID in test group: %d", id)
+			}
+			groupedTestIDs[id] = true
+		}
+		err = t.AddTestGroupWithID(g.ID, g.Points, g.Public, slices.Clone(g.TestIDs), g.Subtask)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i, e := range tj.Examples {
+		input, err := d.decode(e.Input)
+		if err != nil {
+			return nil, fmt.Errorf("example %d input: %w", i+1, err)
+		}
+		output, err := d.decode(e.Output)
+		if err != nil {
+			return nil, fmt.Errorf("example %d output: %w", i+1, err)
+		}
+		note, err := d.decodePtr(e.MdNote)
+		if err != nil {
+			return nil, fmt.Errorf("example %d note: %w", i+1, err)
+		}
+		t.examples = append(t.examples, example{
+			Input:     input,
+			Output:    output,
+			MdNote:    note,
+			Name:      clonePtr(e.Name),
+			InputExt:  e.InputExt,
+			OutputExt: e.OutputExt,
+		})
+	}
+
+	for _, st := range tj.MarkdownStatements {
+		if st.Language == nil {
+			return nil, fmt.Errorf("markdown statement without a language")
+		}
+		if t.findMarkdownStatement(*st.Language) >= 0 {
+			return nil, fmt.Errorf("duplicate markdown statement for language %s", *st.Language)
+		}
+		err = t.SetMarkdownStatement(*st.Language, MarkdownStatement(mDStatement(st).clone()))
+		if err != nil {
+			return nil, fmt.Errorf("markdown statement: %w", err)
+		}
+	}
+
+	for lang, b := range tj.PDFStatements {
+		normalized, err := normalizeLanguageCode(lang)
+		if err != nil {
+			return nil, fmt.Errorf("pdf statement: %w", err)
+		}
+		if _, ok := t.pdfStatements[normalized]; ok {
+			return nil, fmt.Errorf("duplicate pdf statement for language %s", normalized)
+		}
+		pdf, err := d.decode(b)
+		if err != nil {
+			return nil, fmt.Errorf("pdf statement %s: %w", lang, err)
+		}
+		t.pdfStatements[normalized] = pdf
+	}
+
+	for lang, e := range tj.Editorials {
+		normalized, err := normalizeLanguageCode(lang)
+		if err != nil {
+			return nil, fmt.Errorf("editorial: %w", err)
+		}
+		if _, ok := t.editorials[normalized]; ok {
+			return nil, fmt.Errorf("duplicate editorial for language %s", normalized)
+		}
+		pdf, err := d.decodePtr(e.PDF)
+		if err != nil {
+			return nil, fmt.Errorf("editorial %s: %w", lang, err)
+		}
+		assets, err := d.decodeAssets(e.Assets)
+		if err != nil {
+			return nil, fmt.Errorf("editorial %s: %w", lang, err)
+		}
+		ed := Editorial{Markdown: clonePtr(e.Markdown), PDF: pdf}
+		for _, a := range assets {
+			ed.Assets = append(ed.Assets, Asset(a))
+		}
+		err = t.SetEditorial(normalized, ed)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return t, nil
+}
+
+// clone copies the statement, so that the task and TaskJSON do not share it
+func (st mDStatement) clone() mDStatement {
+	st.Language = clonePtr(st.Language)
+	st.Notes = clonePtr(st.Notes)
+	st.Scoring = clonePtr(st.Scoring)
+	st.Sections = maps.Clone(st.Sections)
+	return st
+}
+
+func clonePtr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
+
+// MarshalJSON writes the task as TaskJSON with inline contents
+func (t *Task) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.ToJSON(JSONOptions{Contents: JSONContentInline}))
+}
+
+// UnmarshalJSON reads a TaskJSON with inline contents
+func (t *Task) UnmarshalJSON(data []byte) error {
+	var tj TaskJSON
+	err := json.Unmarshal(data, &tj)
+	if err != nil {
+		return err
+	}
+
+	res, err := TaskFromJSON(tj, nil)
+	if err != nil {
+		return err
+	}
+	*t = *res
+	return nil
+}
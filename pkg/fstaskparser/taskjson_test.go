@@ -0,0 +1,189 @@
+package fstaskparser_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/programme-lv/fs-task-format-parser/pkg/fstaskparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskJSONRoundTrip(t *testing.T) {
+	task, err := fstaskparser.Read(testTaskPath)
+	require.NoErrorf(t, err, "failed to read task: %v", err)
+
+	editorial := "Izmanto prefiksu summas.\n"
+	err = task.SetEditorial("lv", fstaskparser.Editorial{Markdown: &editorial})
+	require.NoErrorf(t, err, "failed to set editorial: %v", err)
+	task.SetCheckerSource([]byte("int main() {}\n"))
+	err = task.SetStatementSectionVisibility("hints", fstaskparser.SectionVisibleToContestants)
+	require.NoErrorf(t, err, "failed to set section visibility: %v", err)
+
+	content, err := json.Marshal(task)
+	require.NoErrorf(t, err, "failed to marshal task: %v", err)
+
+	var restored fstaskparser.Task
+	err = json.Unmarshal(content, &restored)
+	require.NoErrorf(t, err, "failed to unmarshal task: %v", err)
+
+	again, err := json.Marshal(&restored)
+	require.NoErrorf(t, err, "failed to marshal restored task: %v", err)
+	assert.JSONEq(t, string(content), string(again))
+
+	assert.Equal(t, task.GetTestsSortedByID(), restored.GetTestsSortedByID())
+	assert.Equal(t, task.GetExamples(), restored.GetExamples())
+	assert.Equal(t, task.GetMarkdownStatements(), restored.GetMarkdownStatements())
+	assert.Equal(t, task.GetAllEditorials(), restored.GetAllEditorials())
+	assert.Equal(t, task.GetOriginNotes(), restored.GetOriginNotes())
+	assert.Equal(t, task.GetVisibleInputSubtasks(), restored.GetVisibleInputSubtasks())
+	assert.Equal(t, task.GetTaskIllustrationImage(), restored.GetTaskIllustrationImage())
+
+	// both tasks are stored the same way
+	tmpDirectory, err := os.MkdirTemp("", "fstaskparser-test-")
+	require.NoErrorf(t, err, "failed to create temporary directory: %v", err)
+	defer os.RemoveAll(tmpDirectory)
+
+	err = task.Store(filepath.Join(tmpDirectory, "original"))
+	require.NoErrorf(t, err, "failed to store task: %v", err)
+	err = restored.Store(filepath.Join(tmpDirectory, "restored"))
+	require.NoErrorf(t, err, "failed to store restored task: %v", err)
+
+	expected, err := os.ReadFile(filepath.Join(tmpDirectory, "original", "problem.toml"))
+	require.NoErrorf(t, err, "failed to read problem.toml: %v", err)
+	actual, err := os.ReadFile(filepath.Join(tmpDirectory, "restored", "problem.toml"))
+	require.NoErrorf(t, err, "failed to read problem.toml: %v", err)
+	assert.Equal(t, string(expected), string(actual))
+}
+
+func TestTaskJSONWithHashedContents(t *testing.T) {
+	task, err := fstaskparser.Read(testTaskPath)
+	require.NoErrorf(t, err, "failed to read task: %v", err)
+
+	// contents are kept aside by hash, as a blob store would
+	blobs := make(map[string][]byte)
+	inline := task.ToJSON(fstaskparser.JSONOptions{Contents: fstaskparser.JSONContentInline})
+	for _, test := range inline.Tests {
+		blobs[test.Input.SHA256] = test.Input.Base64
+		blobs[test.Answer.SHA256] = test.Answer.Base64
+	}
+	for _, e := range inline.Examples {
+		blobs[e.Input.SHA256] = e.Input.Base64
+		blobs[e.Output.SHA256] = e.Output.Base64
+		if e.MdNote != nil {
+			blobs[e.MdNote.SHA256] = e.MdNote.Base64
+		}
+	}
+	for _, pdf := range inline.PDFStatements {
+		blobs[pdf.SHA256] = pdf.Base64
+	}
+	for _, a := range inline.Assets {
+		blobs[a.Content.SHA256] = a.Content.Base64
+	}
+	resolve := func(sha256 string) ([]byte, error) {
+		content, ok := blobs[sha256]
+		if !ok {
+			return nil, fmt.Errorf("blob %s not found", sha256)
+		}
+		return content, nil
+	}
+
+	hashed := task.ToJSON(fstaskparser.JSONOptions{Contents: fstaskparser.JSONContentHash})
+	require.NotEmpty(t, hashed.Tests)
+	assert.Nil(t, hashed.Tests[0].Input.Base64)
+	assert.Equal(t, inline.Tests[0].Input.SHA256, hashed.Tests[0].Input.SHA256)
+
+	content, err := json.Marshal(hashed)
+	require.NoErrorf(t, err, "failed to marshal task: %v", err)
+	assert.NotContains(t, string(content), "base64")
+
+	_, err = fstaskparser.TaskFromJSON(hashed, nil)
+	require.Error(t, err)
+
+	restored, err := fstaskparser.TaskFromJSON(hashed, resolve)
+	require.NoErrorf(t, err, "failed to restore task: %v", err)
+	assert.Equal(t, task.GetTestsSortedByID(), restored.GetTestsSortedByID())
+	assert.Equal(t, task.ToJSON(fstaskparser.JSONOptions{}), restored.ToJSON(fstaskparser.JSONOptions{}))
+
+	// contents that do not match their hash are rejected
+	blobs[hashed.Tests[0].Input.SHA256] = []byte("tampered\n")
+	_, err = fstaskparser.TaskFromJSON(hashed, resolve)
+	require.Error(t, err)
+}
+
+func TestTaskJSONIsCopiedAndValidated(t *testing.T) {
+	task, err := fstaskparser.Read(testTaskPath)
+	require.NoErrorf(t, err, "failed to read task: %v", err)
+
+	// changing the JSON representation does not change the task
+	tj := task.ToJSON(fstaskparser.JSONOptions{})
+	tj.OriginNotes["lv"] = "changed"
+	tj.TestGroups[0].TestIDs[0] = 99
+	tj.VisibleInputSubtasks[0] = 99
+	tj.Tests[0].Input.Base64[0] = 'x'
+	assert.NotEqual(t, "changed", task.GetOriginNotes()["lv"])
+	assert.Equal(t, []int{1, 2, 3}, task.GetInfoOnTestGroup(1).TestIDs)
+	assert.Equal(t, []int{1}, task.GetVisibleInputSubtasks())
+	assert.NotEqual(t, byte('x'), task.GetTestsSortedByID()[0].Input[0])
+
+	// and the other way round
+	tj = task.ToJSON(fstaskparser.JSONOptions{})
+	restored, err := fstaskparser.TaskFromJSON(tj, nil)
+	require.NoErrorf(t, err, "failed to restore task: %v", err)
+	tj.OriginNotes["lv"] = "changed"
+	tj.TestGroups[0].TestIDs[0] = 99
+	*tj.MarkdownStatements[0].Language = "en"
+	assert.NotEqual(t, "changed", restored.GetOriginNotes()["lv"])
+	assert.Equal(t, []int{1, 2, 3}, restored.GetInfoOnTestGroup(1).TestIDs)
+	_, err = restored.GetMarkdownStatement("lv")
+	require.NoErrorf(t, err, "failed to get statement: %v", err)
+
+	// tasks that Store could not write back are rejected
+	for name, corrupt := range map[string]func(tj *fstaskparser.TaskJSON){
+		"duplicate test id":    func(tj *fstaskparser.TaskJSON) { tj.Tests[1].ID = tj.Tests[0].ID },
+		"unknown grouped test": func(tj *fstaskparser.TaskJSON) { tj.TestGroups[0].TestIDs = []int{99} },
+		"difficulty":           func(tj *fstaskparser.TaskJSON) { tj.DifficultyOneToFive = 6 },
+		"statement language":   func(tj *fstaskparser.TaskJSON) { *tj.MarkdownStatements[0].Language = "not a language" },
+		"pdf language": func(tj *fstaskparser.TaskJSON) {
+			tj.PDFStatements["??"] = tj.PDFStatements["lv"]
+		},
+		"editorial language": func(tj *fstaskparser.TaskJSON) {
+			tj.Editorials["??"] = fstaskparser.EditorialJSON{PDF: &tj.Tests[0].Input}
+		},
+		"nil statement language": func(tj *fstaskparser.TaskJSON) { tj.MarkdownStatements[0].Language = nil },
+		"duplicate pdf language": func(tj *fstaskparser.TaskJSON) {
+			tj.PDFStatements["LV"] = tj.PDFStatements["lv"]
+		},
+		"empty editorial": func(tj *fstaskparser.TaskJSON) { tj.Editorials["en"] = fstaskparser.EditorialJSON{} },
+		"asset path":      func(tj *fstaskparser.TaskJSON) { tj.Assets[0].RelativePath = "../../escaped.txt" },
+		"absolute path":   func(tj *fstaskparser.TaskJSON) { tj.Assets[0].RelativePath = "/escaped.txt" },
+		"backslash path":  func(tj *fstaskparser.TaskJSON) { tj.Assets[0].RelativePath = `..\escaped.txt` },
+		"test filename":   func(tj *fstaskparser.TaskJSON) { tj.Tests[0].Filename = "../escaped" },
+		"test file pattern": func(tj *fstaskparser.TaskJSON) {
+			tj.TestFilePatterns = map[string]fstaskparser.TestFilePattern{tj.Tests[0].Filename: {Input: "../*.in", Answer: "*.out"}}
+		},
+		"editorial asset path": func(tj *fstaskparser.TaskJSON) {
+			tj.Editorials["en"] = fstaskparser.EditorialJSON{PDF: &tj.Tests[0].Input,
+				Assets: []fstaskparser.AssetJSON{{RelativePath: "../escaped.png", Content: tj.Tests[0].Input}}}
+		},
+	} {
+		tj := task.ToJSON(fstaskparser.JSONOptions{})
+		corrupt(&tj)
+		_, err := fstaskparser.TaskFromJSON(tj, nil)
+		require.Errorf(t, err, "%s accepted", name)
+	}
+
+	// languages are normalized like the statement setters do
+	tj = task.ToJSON(fstaskparser.JSONOptions{})
+	tj.PDFStatements = map[string]fstaskparser.JSONBlob{"LV": tj.PDFStatements["lv"]}
+	tj.Editorials = map[string]fstaskparser.EditorialJSON{"EN-us": {PDF: &tj.Tests[0].Input}}
+	restored, err = fstaskparser.TaskFromJSON(tj, nil)
+	require.NoErrorf(t, err, "failed to restore task: %v", err)
+	_, err = restored.GetPDFStatement("lv")
+	require.NoErrorf(t, err, "failed to get pdf statement: %v", err)
+	_, err = restored.GetEditorial("en-US")
+	require.NoErrorf(t, err, "failed to get editorial: %v", err)
+}
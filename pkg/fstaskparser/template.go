@@ -0,0 +1,159 @@
+package fstaskparser
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Markdown statements and example notes may contain placeholders that are
+// resolved from the task when the statement is rendered or exported:
+//
+//	{{time_limit}}          CPU time limit in seconds, e.g. "0.5"
+//	{{memory_limit}}        memory limit in megabytes, e.g. "256"
+//	{{subtask_points 2}}    sum of the test group points of subtask 2
+//	{{example 1 input}}     input of the first example, "output" for its output
+//
+// The statement files keep the placeholders, Read and Store do not resolve them.
+
+// TemplateProblem is a placeholder in a Markdown statement or example note
+// that cannot be resolved.
+type TemplateProblem struct {
+	Language    string // empty for example notes
+	Section     string // "story", "input", "output", "notes", "scoring", an additional section or "examples/<name>"
+	Line        int    // 1-based line within the section
+	Placeholder string // placeholder including its braces
+	Message     string
+}
+
+func (p TemplateProblem) String() string {
+	if p.Language == "" {
+		return fmt.Sprintf("%s:%d: %s: %s", p.Section, p.Line, p.Placeholder, p.Message)
+	}
+	return fmt.Sprintf("%s/%s:%d: %s: %s", p.Language, p.Section, p.Line, p.Placeholder, p.Message)
+}
+
+var templatePlaceholderRegexp = regexp.MustCompile(`\{\{\s*([^{}]*?)\s*\}\}`)
+
+// resolvePlaceholder returns the value of a placeholder given its contents
+// between the braces, e.g. "subtask_points 2".
+func (t *Task) resolvePlaceholder(placeholder string) (string, error) {
+	fields := strings.Fields(placeholder)
+	if len(fields) == 0 {
+		return "", fmt.Errorf("empty placeholder")
+	}
+
+	name, args := fields[0], fields[1:]
+	switch name {
+	case "time_limit":
+		if len(args) != 0 {
+			return "", fmt.Errorf("time_limit takes no arguments")
+		}
+		return strconv.FormatFloat(t.cpuTimeSeconds, 'f', -1, 64), nil
+	case "memory_limit":
+		if len(args) != 0 {
+			return "", fmt.Errorf("memory_limit takes no arguments")
+		}
+		return strconv.Itoa(t.memoryMegabytes), nil
+	case "subtask_points":
+		if len(args) != 1 {
+			return "", fmt.Errorf("subtask_points takes a subtask number")
+		}
+		subtask, err := strconv.Atoi(args[0])
+		if err != nil {
+			return "", fmt.Errorf("invalid subtask number %q", args[0])
+		}
+		points, found := 0, false
+		for _, groupID := range t.testGroupIDs {
+			if t.tGroupToStMap[groupID] == subtask {
+				points += t.tGroupPoints[groupID]
+				found = true
+			}
+		}
+		if !found {
+			return "", fmt.Errorf("subtask %d has no test groups", subtask)
+		}
+		return strconv.Itoa(points), nil
+	case "example":
+		if len(args) != 2 {
+			return "", fmt.Errorf("example takes an example number and \"input\" or \"output\"")
+		}
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 1 || n > len(t.examples) {
+			return "", fmt.Errorf("example %s does not exist", args[0])
+		}
+		switch args[1] {
+		case "input":
+			return strings.TrimRight(string(t.examples[n-1].Input), "\r\n"), nil
+		case "output":
+			return strings.TrimRight(string(t.examples[n-1].Output), "\r\n"), nil
+		default:
+			return "", fmt.Errorf("example part must be \"input\" or \"output\", not %q", args[1])
+		}
+	default:
+		return "", fmt.Errorf("unknown placeholder %s", name)
+	}
+}
+
+// resolveTemplate replaces the placeholders in md with their values.
+// Placeholders that cannot be resolved are left as they are.
+func (t *Task) resolveTemplate(md string) string {
+	return templatePlaceholderRegexp.ReplaceAllStringFunc(md, func(m string) string {
+		inner := templatePlaceholderRegexp.FindStringSubmatch(m)[1]
+		value, err := t.resolvePlaceholder(inner)
+		if err != nil {
+			return m
+		}
+		return value
+	})
+}
+
+// GetResolvedMarkdownStatements returns the Markdown statements with their
+// placeholders resolved, as they should be shown to contestants.
+func (t *Task) GetResolvedMarkdownStatements() []MarkdownStatement {
+	res := t.GetMarkdownStatements()
+	for i := range res {
+		res[i].Story = t.resolveTemplate(res[i].Story)
+		res[i].Input = t.resolveTemplate(res[i].Input)
+		res[i].Output = t.resolveTemplate(res[i].Output)
+		if res[i].Notes != nil {
+			notes := t.resolveTemplate(*res[i].Notes)
+			res[i].Notes = &notes
+		}
+		if res[i].Scoring != nil {
+			scoring := t.resolveTemplate(*res[i].Scoring)
+			res[i].Scoring = &scoring
+		}
+		if res[i].Sections != nil {
+			sections := make(map[string]string, len(res[i].Sections))
+			for name, md := range res[i].Sections {
+				sections[name] = t.resolveTemplate(md)
+			}
+			res[i].Sections = sections
+		}
+	}
+	return res
+}
+
+// LintStatementTemplates reports the placeholders of every Markdown statement
+// section and example note that are unknown or cannot be resolved.
+func (t *Task) LintStatementTemplates() []TemplateProblem {
+	res := make([]TemplateProblem, 0)
+	t.forEachMarkdownSection(func(lang string, section string, md string) {
+		for _, m := range templatePlaceholderRegexp.FindAllStringSubmatchIndex(md, -1) {
+			_, err := t.resolvePlaceholder(md[m[2]:m[3]])
+			if err == nil {
+				continue
+			}
+			res = append(res, TemplateProblem{
+				Language:    lang,
+				Section:     section,
+				Line:        strings.Count(md[:m[0]], "\n") + 1,
+				Placeholder: md[m[0]:m[1]],
+				Message:     err.Error(),
+			})
+		}
+	})
+	return res
+}
@@ -0,0 +1,67 @@
+package fstaskparser_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/programme-lv/fs-task-format-parser/pkg/fstaskparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolvingStatementPlaceholders(t *testing.T) {
+	parsedTask, err := fstaskparser.Read(testTaskPath)
+	require.NoErrorf(t, err, "failed to read task: %v", err)
+	assert.Empty(t, parsedTask.LintStatementTemplates())
+
+	tmpDirectory, err := os.MkdirTemp("", "fstaskparser-test-")
+	require.NoErrorf(t, err, "failed to create temporary directory: %v", err)
+	defer os.RemoveAll(tmpDirectory)
+
+	outputDirectory := filepath.Join(tmpDirectory, "kvadrputekl")
+	err = parsedTask.Store(outputDirectory)
+	require.NoErrorf(t, err, "failed to store task: %v", err)
+
+	inputPath := filepath.Join(outputDirectory, "statements", "md", "lv", "input.md")
+	template := "Laika limits: {{time_limit}} s, atmiņa: {{ memory_limit }} MB.\n" +
+		"Otrā apakšuzdevuma punkti: {{subtask_points 2}}.\n" +
+		"Piemērs: `{{example 1 output}}`\n" +
+		"{{author_name}} un {{subtask_points 9}}\n"
+	err = os.WriteFile(inputPath, []byte(template), 0644)
+	require.NoErrorf(t, err, "failed to write input.md: %v", err)
+
+	storedTask, err := fstaskparser.Read(outputDirectory)
+	require.NoErrorf(t, err, "failed to read task: %v", err)
+
+	// the raw template is kept
+	statements := storedTask.GetMarkdownStatements()
+	require.Equal(t, 1, len(statements))
+	assert.Equal(t, template, statements[0].Input)
+
+	resolved := storedTask.GetResolvedMarkdownStatements()
+	assert.Equal(t, "Laika limits: 0.5 s, atmiņa: 256 MB.\n"+
+		"Otrā apakšuzdevuma punkti: 8.\n"+
+		"Piemērs: `10`\n"+
+		"{{author_name}} un {{subtask_points 9}}\n", resolved[0].Input)
+
+	problems := storedTask.LintStatementTemplates()
+	require.Equal(t, 2, len(problems))
+	assert.Equal(t, "lv", problems[0].Language)
+	assert.Equal(t, "input", problems[0].Section)
+	assert.Equal(t, 4, problems[0].Line)
+	assert.Equal(t, "{{author_name}}", problems[0].Placeholder)
+	assert.Equal(t, "unknown placeholder author_name", problems[0].Message)
+	assert.Equal(t, "subtask 9 has no test groups", problems[1].Message)
+
+	rendered, err := storedTask.RenderStatementHTML("lv", fstaskparser.RenderOptions{Fragment: true})
+	require.NoErrorf(t, err, "failed to render statement: %v", err)
+	assert.Contains(t, string(rendered), "Laika limits: 0.5 s, atmiņa: 256 MB.")
+
+	anotherOutputDirectory := filepath.Join(tmpDirectory, "kvadrputekl2")
+	err = storedTask.Store(anotherOutputDirectory)
+	require.NoErrorf(t, err, "failed to store task: %v", err)
+	stored, err := os.ReadFile(filepath.Join(anotherOutputDirectory, "statements", "md", "lv", "input.md"))
+	require.NoErrorf(t, err, "failed to read input.md: %v", err)
+	assert.Equal(t, template, string(stored))
+}
@@ -0,0 +1,320 @@
+package fstaskparser
+
+import (
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TestFilePattern describes how the input and answer files of a test are named.
+// Both patterns contain exactly one "*" which stands for the test filename,
+// e.g. {"*.in", "*.out"} pairs "kp01a.in" with "kp01a.out" and
+// {"input.*", "output.*"} pairs "input.001" with "output.001".
+type TestFilePattern struct {
+	Input  string
+	Answer string
+}
+
+// DefaultTestFilePatterns are used when ReadOptions.TestFilePatterns is empty.
+// Earlier patterns take precedence when a file matches several of them.
+var DefaultTestFilePatterns = []TestFilePattern{
+	{Input: "*.in", Answer: "*.out"},
+	{Input: "*.in", Answer: "*.ans"},
+	{Input: "*.in", Answer: "*.a"},
+	{Input: "input.*", Answer: "output.*"},
+}
+
+var defaultTestFilePattern = DefaultTestFilePatterns[0]
+
+// ReadDiagnostic is a non-fatal problem found while reading a task directory.
+type ReadDiagnostic struct {
+	Path    string // path relative to the task root directory
+	Message string
+}
+
+func (d ReadDiagnostic) String() string {
+	return fmt.Sprintf("%s: %s", d.Path, d.Message)
+}
+
+// matchFilePattern returns the part of fname matched by "*" in pattern.
+func matchFilePattern(pattern string, fname string) (string, bool) {
+	prefix, suffix, ok := strings.Cut(pattern, "*")
+	if !ok {
+		return "", false
+	}
+	if len(fname) <= len(prefix)+len(suffix) {
+		return "", false
+	}
+	if !strings.HasPrefix(fname, prefix) || !strings.HasSuffix(fname, suffix) {
+		return "", false
+	}
+	return fname[len(prefix) : len(fname)-len(suffix)], true
+}
+
+func formatFilePattern(pattern string, name string) string {
+	return strings.Replace(pattern, "*", name, 1)
+}
+
+func validateTestFilePatterns(patterns []TestFilePattern) error {
+	for _, p := range patterns {
+		if strings.Count(p.Input, "*") != 1 || strings.Count(p.Answer, "*") != 1 {
+			return fmt.Errorf("test file pattern must contain exactly one \"*\": %+v", p)
+		}
+		if p.Input == p.Answer {
+			return fmt.Errorf("test file pattern input and answer must differ: %+v", p)
+		}
+	}
+	return nil
+}
+
+// testFile is a pair of input and answer files found in the tests directory.
+type testFile struct {
+	Fname       string // test filename without extension, e.g. "kp01a"
+	InputPath   string // path relative to the tests directory
+	AnswerPath  string
+	FilePattern TestFilePattern
+}
+
+// scanTestsDir pairs the files of the tests directory by test filename.
+// Nested directories are scanned too, except hidden ones, and their relative
+// path becomes part of the test filename, e.g. "subtask1/01". Files that do not match any
+// pattern, or miss their pair, are reported as diagnostics instead of
+// failing the whole directory.
+func scanTestsDir(dirPath string, patterns []TestFilePattern) ([]testFile, []ReadDiagnostic, error) {
+	log.Printf("Scanning tests directory: %s\n", dirPath)
+	if _, err := os.Stat(dirPath); err != nil {
+		log.Printf("Error reading tests directory: %v\n", err)
+		return nil, nil, fmt.Errorf("error reading tests directory: %w", err)
+	}
+
+	diagnostics := make([]ReadDiagnostic, 0)
+	inputs := make(map[string]string)
+	answers := make(map[string]string)
+	inputPattern := make(map[string]int)
+	answerPattern := make(map[string]int)
+
+	// WalkDir visits entries in lexical order
+	err := filepath.WalkDir(dirPath, func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			// hidden directories such as .git do not hold tests
+			if p != dirPath && strings.HasPrefix(entry.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		rel, err := filepath.Rel(dirPath, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		relPath := "tests/" + rel
+		dir, base := path.Split(rel)
+
+		for i, pat := range patterns {
+			if name, ok := matchFilePattern(pat.Input, base); ok {
+				name = dir + name
+				if prev, exists := inputs[name]; exists {
+					diagnostics = append(diagnostics, ReadDiagnostic{Path: relPath,
+						Message: fmt.Sprintf("duplicate input file for test %s, using %s", name, prev)})
+				} else {
+					inputs[name] = rel
+					inputPattern[name] = i
+				}
+				return nil
+			}
+			if name, ok := matchFilePattern(pat.Answer, base); ok {
+				name = dir + name
+				if prev, exists := answers[name]; exists {
+					diagnostics = append(diagnostics, ReadDiagnostic{Path: relPath,
+						Message: fmt.Sprintf("duplicate answer file for test %s, using %s", name, prev)})
+				} else {
+					answers[name] = rel
+					answerPattern[name] = i
+				}
+				return nil
+			}
+		}
+
+		diagnostics = append(diagnostics, ReadDiagnostic{Path: relPath, Message: "file does not match any test file pattern"})
+		return nil
+	})
+	if err != nil {
+		log.Printf("Error reading tests directory: %v\n", err)
+		return nil, nil, fmt.Errorf("error reading tests directory: %w", err)
+	}
+
+	res := make([]testFile, 0, len(inputs))
+	for name, in := range inputs {
+		ans, ok := answers[name]
+		if !ok {
+			diagnostics = append(diagnostics, ReadDiagnostic{Path: "tests/" + in, Message: "input file has no answer file"})
+			continue
+		}
+		res = append(res, testFile{
+			Fname:      name,
+			InputPath:  in,
+			AnswerPath: ans,
+			FilePattern: TestFilePattern{
+				Input:  patterns[inputPattern[name]].Input,
+				Answer: patterns[answerPattern[name]].Answer,
+			},
+		})
+	}
+	for name, ans := range answers {
+		if _, ok := inputs[name]; !ok {
+			diagnostics = append(diagnostics, ReadDiagnostic{Path: "tests/" + ans, Message: "answer file has no input file"})
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Fname < res[j].Fname
+	})
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		return diagnostics[i].Path < diagnostics[j].Path
+	})
+
+	for _, d := range diagnostics {
+		log.Printf("Warning: %s\n", d)
+	}
+
+	log.Printf("Found %d tests\n", len(res))
+	return res, diagnostics, nil
+}
+
+// testFilePath returns the slash separated path of a test file relative to
+// the tests directory, keeping the directories of the test filename.
+func testFilePath(pattern string, fname string) string {
+	dir, base := path.Split(fname)
+	return dir + formatFilePattern(pattern, base)
+}
+
+// TestDirGrouping selects how nested test directories are turned into test groups.
+type TestDirGrouping int
+
+const (
+	// TestDirGroupingNone keeps directories as part of test filenames only
+	TestDirGroupingNone TestDirGrouping = iota
+	// TestDirGroupingGroups makes every top-level directory a test group
+	TestDirGroupingGroups
+	// TestDirGroupingSubtasks makes every top-level directory a test group of
+	// the subtask numbered in the directory name, e.g. "subtask2" or "st2"
+	TestDirGroupingSubtasks
+)
+
+// groupTestsByDir creates a test group for every top-level directory of
+// the tests directory. Tests outside of directories stay ungrouped. The
+// groups get 0 points, which is reported as a read diagnostic.
+func (t *Task) groupTestsByDir(grouping TestDirGrouping) error {
+	dirs := make([]string, 0)
+	dirTestIDs := make(map[string][]int)
+	for _, fname := range t.testFnamesSorted {
+		dir, _, ok := strings.Cut(fname, "/")
+		if !ok {
+			continue
+		}
+		if _, seen := dirTestIDs[dir]; !seen {
+			dirs = append(dirs, dir)
+		}
+		dirTestIDs[dir] = append(dirTestIDs[dir], t.testFilenameToID[fname])
+	}
+
+	for _, dir := range dirs {
+		subtask := 0
+		if grouping == TestDirGroupingSubtasks {
+			n, err := trailingNumber(dir)
+			if err != nil {
+				return fmt.Errorf("no subtask number in test directory name %q", dir)
+			}
+			subtask = n
+		}
+
+		testIDs := dirTestIDs[dir]
+		sort.Ints(testIDs)
+		groupID := t.testGroupMexPositiveID()
+		err := t.AddTestGroupWithID(groupID, 0, false, testIDs, subtask)
+		if err != nil {
+			return err
+		}
+		log.Printf("Created test group from directory %s with %d tests\n", dir, len(testIDs))
+		t.readDiagnostics = append(t.readDiagnostics, ReadDiagnostic{Path: "tests/" + dir,
+			Message: fmt.Sprintf("test group %d created from the directory has 0 points", groupID)})
+	}
+
+	return nil
+}
+
+// MoveTestsIntoGroupDirs prefixes test filenames with a directory per test
+// group ("group1", ...) or per subtask ("subtask1", ...), so that Store
+// writes a nested tests directory. Tests outside of groups are not moved.
+func (t *Task) MoveTestsIntoGroupDirs(grouping TestDirGrouping) error {
+	if grouping == TestDirGroupingNone {
+		return nil
+	}
+
+	renames := make(map[int]string)
+	taken := make(map[string]bool)
+	for _, groupID := range t.testGroupIDs {
+		dir := fmt.Sprintf("group%d", groupID)
+		if grouping == TestDirGroupingSubtasks {
+			dir = fmt.Sprintf("subtask%d", t.tGroupToStMap[groupID])
+		}
+		for _, testID := range t.tGroupTestIDs[groupID] {
+			fname := path.Join(dir, path.Base(t.getTestToBeWrittenFname(testID)))
+			if taken[fname] {
+				return fmt.Errorf("test filename %s is not unique", fname)
+			}
+			taken[fname] = true
+			renames[testID] = fname
+		}
+	}
+
+	// check for conflicts with tests that keep their filename before renaming
+	for _, fname := range renames {
+		if owner, exists := t.testFilenameToID[fname]; exists {
+			if _, renamed := renames[owner]; !renamed {
+				return fmt.Errorf("test filename %s already exists", fname)
+			}
+		}
+	}
+
+	patterns := make(map[int]TestFilePattern)
+	for testID := range renames {
+		oldFname, ok := t.testIDToFilename[testID]
+		if !ok {
+			continue
+		}
+		if pattern, ok := t.testFilePatterns[oldFname]; ok {
+			patterns[testID] = pattern
+		}
+		delete(t.testFilenameToID, oldFname)
+		delete(t.testFilePatterns, oldFname)
+	}
+
+	for testID, fname := range renames {
+		t.testIDToFilename[testID] = fname
+		t.testFilenameToID[fname] = testID
+		if pattern, ok := patterns[testID]; ok {
+			t.testFilePatterns[fname] = pattern
+		}
+	}
+
+	return nil
+}
+
+func trailingNumber(s string) (int, error) {
+	i := len(s)
+	for i > 0 && s[i-1] >= '0' && s[i-1] <= '9' {
+		i--
+	}
+	return strconv.Atoi(s[i:])
+}
@@ -0,0 +1,158 @@
+package fstaskparser_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/programme-lv/fs-task-format-parser/pkg/fstaskparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadingTestsWithStrayFiles(t *testing.T) {
+	parsedTask, err := fstaskparser.Read(testTaskPath)
+	require.NoErrorf(t, err, "failed to read task: %v", err)
+
+	tmpDirectory, err := os.MkdirTemp("", "fstaskparser-test-")
+	require.NoErrorf(t, err, "failed to create temporary directory: %v", err)
+	defer os.RemoveAll(tmpDirectory)
+
+	outputDirectory := filepath.Join(tmpDirectory, "kvadrputekl")
+	err = parsedTask.Store(outputDirectory)
+	require.NoErrorf(t, err, "failed to store task: %v", err)
+
+	testsDir := filepath.Join(outputDirectory, "tests")
+	for _, fname := range []string{"README", ".gitkeep", "kp01a.sol", "kp03a.in"} {
+		err = os.WriteFile(filepath.Join(testsDir, fname), []byte("stray"), 0644)
+		require.NoErrorf(t, err, "failed to write file: %v", err)
+	}
+	err = os.Mkdir(filepath.Join(testsDir, "generators"), 0755)
+	require.NoErrorf(t, err, "failed to create directory: %v", err)
+	err = os.WriteFile(filepath.Join(testsDir, "generators", "gen.py"), []byte("print(1)"), 0644)
+	require.NoErrorf(t, err, "failed to write file: %v", err)
+
+	storedTask, err := fstaskparser.Read(outputDirectory)
+	require.NoErrorf(t, err, "failed to read task: %v", err)
+	assert.Equal(t, parsedTask.GetTestsSortedByID(), storedTask.GetTestsSortedByID())
+	assert.Equal(t, "kp02c", storedTask.GetTestFilenameFromID(6))
+
+	diagnosticPaths := []string{}
+	for _, d := range storedTask.GetReadDiagnostics() {
+		diagnosticPaths = append(diagnosticPaths, d.Path)
+	}
+	expectedPaths := []string{"tests/.gitkeep", "tests/README", "tests/generators/gen.py", "tests/kp01a.sol", "tests/kp03a.in"}
+	assert.Equal(t, expectedPaths, diagnosticPaths)
+}
+
+func TestReadingTestsWithCustomFilePatterns(t *testing.T) {
+	parsedTask, err := fstaskparser.Read(testTaskPath)
+	require.NoErrorf(t, err, "failed to read task: %v", err)
+
+	tmpDirectory, err := os.MkdirTemp("", "fstaskparser-test-")
+	require.NoErrorf(t, err, "failed to create temporary directory: %v", err)
+	defer os.RemoveAll(tmpDirectory)
+
+	outputDirectory := filepath.Join(tmpDirectory, "kvadrputekl")
+	err = parsedTask.Store(outputDirectory)
+	require.NoErrorf(t, err, "failed to store task: %v", err)
+
+	// rename tests to input.kp01a / output.kp01a
+	testsDir := filepath.Join(outputDirectory, "tests")
+	for i := 1; i <= 6; i++ {
+		fname := parsedTask.GetTestFilenameFromID(i)
+		err = os.Rename(filepath.Join(testsDir, fname+".in"), filepath.Join(testsDir, "input."+fname))
+		require.NoErrorf(t, err, "failed to rename file: %v", err)
+		err = os.Rename(filepath.Join(testsDir, fname+".out"), filepath.Join(testsDir, "output."+fname))
+		require.NoErrorf(t, err, "failed to rename file: %v", err)
+	}
+
+	storedTask, err := fstaskparser.Read(outputDirectory)
+	require.NoErrorf(t, err, "failed to read task: %v", err)
+	assert.Equal(t, parsedTask.GetTestsSortedByID(), storedTask.GetTestsSortedByID())
+	assert.Empty(t, storedTask.GetReadDiagnostics())
+
+	// the naming is kept when storing the task again
+	keptDirectory := filepath.Join(tmpDirectory, "kept")
+	err = storedTask.Store(keptDirectory)
+	require.NoErrorf(t, err, "failed to store task: %v", err)
+	assert.FileExists(t, filepath.Join(keptDirectory, "tests", "input.kp02b"))
+	assert.FileExists(t, filepath.Join(keptDirectory, "tests", "output.kp02b"))
+
+	// with only the default ".in"/".out" pattern nothing matches
+	_, err = fstaskparser.ReadWithOptions(outputDirectory, fstaskparser.ReadOptions{
+		TestFilePatterns: []fstaskparser.TestFilePattern{{Input: "*.in", Answer: "*.out"}},
+	})
+	require.ErrorContains(t, err, "references unknown test")
+
+	_, err = fstaskparser.ReadWithOptions(outputDirectory, fstaskparser.ReadOptions{
+		TestFilePatterns: []fstaskparser.TestFilePattern{{Input: "in", Answer: "out"}},
+	})
+	require.Error(t, err)
+}
+
+func TestReadingWritingNestedTestDirectories(t *testing.T) {
+	parsedTask, err := fstaskparser.Read(testTaskPath)
+	require.NoErrorf(t, err, "failed to read task: %v", err)
+
+	err = parsedTask.MoveTestsIntoGroupDirs(fstaskparser.TestDirGroupingSubtasks)
+	require.NoErrorf(t, err, "failed to move tests into directories: %v", err)
+	assert.Equal(t, "subtask1/kp01a", parsedTask.GetTestFilenameFromID(1))
+	assert.Equal(t, "subtask2/kp02c", parsedTask.GetTestFilenameFromID(6))
+
+	tmpDirectory, err := os.MkdirTemp("", "fstaskparser-test-")
+	require.NoErrorf(t, err, "failed to create temporary directory: %v", err)
+	defer os.RemoveAll(tmpDirectory)
+
+	outputDirectory := filepath.Join(tmpDirectory, "kvadrputekl")
+	err = parsedTask.Store(outputDirectory)
+	require.NoErrorf(t, err, "failed to store task: %v", err)
+	assert.FileExists(t, filepath.Join(outputDirectory, "tests", "subtask1", "kp01a.in"))
+	assert.FileExists(t, filepath.Join(outputDirectory, "tests", "subtask2", "kp02c.out"))
+
+	storedTask, err := fstaskparser.Read(outputDirectory)
+	require.NoErrorf(t, err, "failed to read task: %v", err)
+	assert.Equal(t, parsedTask.GetTestsSortedByID(), storedTask.GetTestsSortedByID())
+	assert.Equal(t, "subtask2/kp02a", storedTask.GetTestFilenameFromID(4))
+	assert.Equal(t, []int{4, 5, 6}, storedTask.GetInfoOnTestGroup(2).TestIDs)
+	assert.Empty(t, storedTask.GetReadDiagnostics())
+
+	// the layout is written back on another round-trip
+	keptDirectory := filepath.Join(tmpDirectory, "kept")
+	err = storedTask.Store(keptDirectory)
+	require.NoErrorf(t, err, "failed to store task: %v", err)
+	assert.FileExists(t, filepath.Join(keptDirectory, "tests", "subtask1", "kp01b.in"))
+
+	// without groups in problem.toml the directories can define them
+	ungroupedTask, err := fstaskparser.NewTask("ungrouped")
+	require.NoErrorf(t, err, "failed to create task: %v", err)
+	for _, test := range storedTask.GetTestsSortedByID() {
+		id := ungroupedTask.AddTest(test.Input, test.Answer)
+		ungroupedTask.AssignFilenameToTest(storedTask.GetTestFilenameFromID(test.ID), id)
+	}
+
+	ungroupedDirectory := filepath.Join(tmpDirectory, "ungrouped")
+	err = ungroupedTask.Store(ungroupedDirectory)
+	require.NoErrorf(t, err, "failed to store task: %v", err)
+
+	// hidden directories are not scanned for tests
+	hiddenDirectory := filepath.Join(ungroupedDirectory, "tests", ".git", "subtask3")
+	require.NoError(t, os.MkdirAll(hiddenDirectory, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(hiddenDirectory, "kp03a.in"), []byte("1\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(hiddenDirectory, "kp03a.out"), []byte("1\n"), 0644))
+
+	groupedTask, err := fstaskparser.ReadWithOptions(ungroupedDirectory, fstaskparser.ReadOptions{
+		TestDirGrouping: fstaskparser.TestDirGroupingSubtasks,
+	})
+	require.NoErrorf(t, err, "failed to read task: %v", err)
+	require.Equal(t, []int{1, 2}, groupedTask.GetTestGroupIDs())
+	assert.Equal(t, []int{1, 2, 3}, groupedTask.GetInfoOnTestGroup(1).TestIDs)
+	assert.Equal(t, 1, groupedTask.GetInfoOnTestGroup(1).Subtask)
+	assert.Equal(t, []int{4, 5, 6}, groupedTask.GetInfoOnTestGroup(2).TestIDs)
+	assert.Equal(t, 2, groupedTask.GetInfoOnTestGroup(2).Subtask)
+	assert.Equal(t, 6, len(groupedTask.GetTestsSortedByID()))
+	assert.Equal(t, []fstaskparser.ReadDiagnostic{
+		{Path: "tests/subtask1", Message: "test group 1 created from the directory has 0 points"},
+		{Path: "tests/subtask2", Message: "test group 2 created from the directory has 0 points"},
+	}, groupedTask.GetReadDiagnostics())
+}
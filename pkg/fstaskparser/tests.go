@@ -7,69 +7,38 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
-
-	"github.com/pelletier/go-toml/v2"
 )
 
 func init() {
 	log.SetFlags(log.LstdFlags | log.Lshortfile)
 }
 
-func readTestsDir(srcDirPath string, fnameToID map[string]int) ([]test, error) {
+func readTestsDir(srcDirPath string, files []testFile, fnameToID map[string]int) ([]test, error) {
 	log.Printf("Reading tests directory: %s\n", srcDirPath)
 	dir := filepath.Join(srcDirPath, "tests")
-	entries, err := os.ReadDir(dir)
-	if err != nil {
-		log.Printf("Error reading tests directory: %v\n", err)
-		return nil, fmt.Errorf("error reading tests directory: %w", err)
-	}
-
-	sort.Slice(entries, func(i, j int) bool {
-		return entries[i].Name() < entries[j].Name()
-	})
-	tests := make([]test, 0, len(entries)/2)
-
-	for i := 0; i < len(entries); i += 2 {
-		inPath := filepath.Join(dir, entries[i].Name())
-		ansPath := filepath.Join(dir, entries[i+1].Name())
-
-		inFilename := entries[i].Name()
-		ansFilename := entries[i+1].Name()
-
-		inFilenameBase := strings.TrimSuffix(inFilename, filepath.Ext(inFilename))
-		ansFilenameBase := strings.TrimSuffix(ansFilename, filepath.Ext(ansFilename))
+	tests := make([]test, 0, len(files))
 
-		if inFilenameBase != ansFilenameBase {
-			log.Printf("Input and answer file base names do not match: %s, %s\n", inFilenameBase, ansFilenameBase)
-			return nil, fmt.Errorf("input and answer file base names do not match: %s, %s", inFilenameBase, ansFilenameBase)
-		}
-
-		// sometimes the test answer is stored as .out, sometimes as .ans
-		if strings.Contains(inFilename, ".ans") || strings.Contains(ansFilename, ".in") {
-			// swap the file paths
-			inPath, ansPath = ansPath, inPath
-		}
-
-		input, err := os.ReadFile(inPath)
+	for _, f := range files {
+		input, err := os.ReadFile(filepath.Join(dir, f.InputPath))
 		if err != nil {
 			log.Printf("Error reading input file: %v\n", err)
 			return nil, fmt.Errorf("error reading input file: %w", err)
 		}
 
-		answer, err := os.ReadFile(ansPath)
+		answer, err := os.ReadFile(filepath.Join(dir, f.AnswerPath))
 		if err != nil {
 			log.Printf("Error reading answer file: %v\n", err)
 			return nil, fmt.Errorf("error reading answer file: %w", err)
 		}
 
 		// check if mapping to id exists
-		if _, ok := fnameToID[inFilenameBase]; !ok {
-			log.Printf("Mapping from filename to id does not exist: %s\n", inFilenameBase)
-			return nil, fmt.Errorf("mapping from filename to id does not exist: %s", inFilenameBase)
+		if _, ok := fnameToID[f.Fname]; !ok {
+			log.Printf("Mapping from filename to id does not exist: %s\n", f.Fname)
+			return nil, fmt.Errorf("mapping from filename to id does not exist: %s", f.Fname)
 		}
 
 		tests = append(tests, test{
-			ID:     fnameToID[inFilenameBase],
+			ID:     fnameToID[f.Fname],
 			Input:  input,
 			Answer: answer,
 		})
@@ -126,6 +95,7 @@ func readExamplesDir(srcDirPath string) ([]example, error) {
 					log.Printf("Error reading input file: %v\n", err)
 					return nil, fmt.Errorf("error reading input file: %w", err)
 				}
+				e.InputExt = filepath.Ext(entry.Name())
 				foundIn = true
 				break
 			}
@@ -144,6 +114,7 @@ func readExamplesDir(srcDirPath string) ([]example, error) {
 					log.Printf("Error reading output file: %v\n", err)
 					return nil, fmt.Errorf("error reading output file: %w", err)
 				}
+				e.OutputExt = filepath.Ext(entry.Name())
 				foundOut = true
 				break
 			}
@@ -172,70 +143,3 @@ func readExamplesDir(srcDirPath string) ([]example, error) {
 	log.Printf("Successfully read examples")
 	return examples, nil
 }
-
-func readTestIDOverwrite(specVers string, tomlContent []byte) (map[string]int, error) {
-	log.Printf("Reading test ID overwrite for specification version: %s\n", specVers)
-	semVerCmpRes, err := getCmpSemVersionsResult(specVers, "v2.3.0")
-	if err != nil {
-		log.Printf("Error comparing sem versions: %v\n", err)
-		return nil, fmt.Errorf("error comparing sem versions: %w", err)
-	}
-
-	if semVerCmpRes < 0 {
-		log.Printf("Warning: skipping reading test ID overwrite (spec version: %s)\n", specVers)
-		// return empty map
-		return make(map[string]int), nil
-	}
-
-	tomlStruct := struct {
-		TestIDOverwrite map[string]int `toml:"test_id_overwrite"`
-	}{}
-
-	err = toml.Unmarshal(tomlContent, &tomlStruct)
-	if err != nil {
-		log.Printf("Failed to unmarshal the test ID overwrite: %v\n", err)
-		return nil, fmt.Errorf("failed to unmarshal the test ID overwrite: %w", err)
-	}
-
-	log.Printf("Successfully read test ID overwrite: %v\n", tomlStruct.TestIDOverwrite)
-	return tomlStruct.TestIDOverwrite, nil
-}
-
-func readTestFNamesSorted(dirPath string) ([]string, error) {
-	log.Printf("Reading test filenames sorted from directory: %s\n", dirPath)
-	fnames, err := os.ReadDir(dirPath)
-	if err != nil {
-		log.Printf("Error reading test filenames: %v\n", err)
-		return nil, fmt.Errorf("error reading test filenames: %w", err)
-	}
-
-	sort.Slice(fnames, func(i, j int) bool {
-		return fnames[i].Name() < fnames[j].Name()
-	})
-
-	if len(fnames)%2 != 0 {
-		log.Printf("Odd number of test filenames: %d\n", len(fnames))
-		return nil, fmt.Errorf("odd number of test filenames")
-	}
-
-	res := make([]string, 0, len(fnames)/2)
-	for i := 0; i < len(fnames); i += 2 {
-		a_name := fnames[i].Name()
-		// remove extension
-		a_name = a_name[:len(a_name)-len(filepath.Ext(a_name))]
-
-		b_name := fnames[i+1].Name()
-		// remove extension
-		b_name = b_name[:len(b_name)-len(filepath.Ext(b_name))]
-
-		if a_name != b_name {
-			log.Printf("Input and answer file base names do not match: %s, %s\n", a_name, b_name)
-			return nil, fmt.Errorf("input and answer file base names do not match: %s, %s", a_name, b_name)
-		}
-
-		res = append(res, a_name)
-	}
-
-	log.Printf("Successfully read test filenames sorted: %v\n", res)
-	return res, nil
-}
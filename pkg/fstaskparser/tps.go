@@ -0,0 +1,427 @@
+package fstaskparser
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// tpsProblemJSON is problem.json of the TPS (IOI) task format
+type tpsProblemJSON struct {
+	Name        string  `json:"name"`
+	Title       string  `json:"title"`
+	Type        string  `json:"type"`
+	TimeLimit   float64 `json:"time_limit"`
+	MemoryLimit int     `json:"memory_limit"`
+	HasChecker  bool    `json:"has_checker"`
+}
+
+// tpsSubtaskJSON is subtasks/<name>.json, tests are listed by name in
+// Testcases or matched by the regular expressions of Regex
+type tpsSubtaskJSON struct {
+	Index     int      `json:"index"`
+	Score     int      `json:"score"`
+	Testcases []string `json:"testcases,omitempty"`
+	Regex     []string `json:"regex,omitempty"`
+}
+
+// TPSExportOptions control ExportTPS.
+type TPSExportOptions struct {
+	// ShortName is problem.json "name", the base name of the directory if empty
+	ShortName string
+}
+
+// tpsUnmappedDirs are task directories without a counterpart in the task
+var tpsUnmappedDirs = []string{"solution", "validator", "gen", "grader", "scripts"}
+
+// ExportTPS writes the task in the TPS layout used at IOI: problem.json,
+// subtasks/<NN>.json with the score and test names of every subtask number,
+// tests/<name>.in and .out, checker/checker.cpp, statement/<lang>.md and
+// .pdf, and the examples in public/examples/. The task holds no
+// solutions, so solution/ is not written.
+func (t *Task) ExportTPS(dirPath string, opts TPSExportOptions) (*ExportReport, error) {
+	report := &ExportReport{Format: "tps"}
+	if _, err := os.Stat(dirPath); !os.IsNotExist(err) {
+		return nil, fmt.Errorf("directory already exists: %s", dirPath)
+	}
+
+	shortName := opts.ShortName
+	if shortName == "" {
+		shortName = filepath.Base(dirPath)
+	}
+
+	files := make(map[string][]byte)
+	writeJSON := func(name string, v any) error {
+		content, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error encoding %s: %w", name, err)
+		}
+		files[name] = append(content, '\n')
+		return nil
+	}
+
+	err := writeJSON("problem.json", tpsProblemJSON{
+		Name:        shortName,
+		Title:       t.taskName,
+		Type:        "Batch",
+		TimeLimit:   t.cpuTimeSeconds,
+		MemoryLimit: t.memoryMegabytes,
+		HasChecker:  t.checker != nil,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	testName := func(id int) string {
+		if name := t.GetTestFilenameFromID(id); name != "" {
+			return strings.ReplaceAll(name, "/", "_")
+		}
+		return fmt.Sprintf("%03d", id)
+	}
+	for _, test := range t.GetTestsSortedByID() {
+		files["tests/"+testName(test.ID)+".in"] = test.Input
+		files["tests/"+testName(test.ID)+".out"] = test.Answer
+	}
+
+	subtasks := make(map[int]*tpsSubtaskJSON)
+	groupCount := make(map[int]int)
+	for _, groupID := range t.testGroupIDs {
+		st := t.tGroupToStMap[groupID]
+		if subtasks[st] == nil {
+			subtasks[st] = &tpsSubtaskJSON{Index: st, Testcases: []string{}}
+		}
+		subtasks[st].Score += t.tGroupPoints[groupID]
+		for _, id := range t.tGroupTestIDs[groupID] {
+			subtasks[st].Testcases = append(subtasks[st].Testcases, testName(id))
+		}
+		groupCount[st]++
+		if t.isTGroupPublic[groupID] {
+			report.dropped("test group %d is public", groupID)
+		}
+	}
+	for _, st := range sortedIntKeys(groupCount) {
+		if n := groupCount[st]; n > 1 {
+			report.dropped("the %d test groups of subtask %d are merged, a subtask scores only when all its tests pass", n, st)
+		}
+	}
+	if len(t.testGroupIDs) > 0 {
+		grouped := make(map[int]bool)
+		for _, groupID := range t.testGroupIDs {
+			for _, id := range t.tGroupTestIDs[groupID] {
+				grouped[id] = true
+			}
+		}
+		ungrouped := make([]string, 0)
+		for _, test := range t.GetTestsSortedByID() {
+			if !grouped[test.ID] {
+				ungrouped = append(ungrouped, testName(test.ID))
+			}
+		}
+		if len(ungrouped) > 0 {
+			// subtask 0 unless the task uses it, then after the last subtask
+			st := 0
+			if subtasks[0] != nil {
+				keys := sortedIntKeys(subtasks)
+				st = keys[len(keys)-1] + 1
+			}
+			subtasks[st] = &tpsSubtaskJSON{Index: st, Score: 0, Testcases: ungrouped}
+			report.dropped("%d tests outside of groups exported as the 0 point subtask %d", len(ungrouped), st)
+		}
+	}
+	var mapping strings.Builder
+	for _, st := range sortedIntKeys(subtasks) {
+		name := fmt.Sprintf("%02d", st)
+		err = writeJSON("subtasks/"+name+".json", subtasks[st])
+		if err != nil {
+			return nil, err
+		}
+		for _, tc := range subtasks[st].Testcases {
+			fmt.Fprintf(&mapping, "%s %s\n", name, tc)
+		}
+	}
+	if mapping.Len() > 0 {
+		files["tests/mapping"] = []byte(mapping.String())
+	}
+	if len(t.visibleInputSubtasks) > 0 {
+		report.dropped("visible input subtasks %v", t.visibleInputSubtasks)
+	}
+
+	if t.checker != nil {
+		files["checker/checker.cpp"] = t.checker
+	}
+
+	for i, e := range t.examples {
+		name := fmt.Sprintf("%02d", i+1)
+		if e.Name != nil {
+			name = *e.Name
+		}
+		files["public/examples/"+name+".in"] = e.Input
+		files["public/examples/"+name+".out"] = e.Output
+		if len(e.MdNote) > 0 {
+			report.dropped("note of example %s", name)
+		}
+	}
+
+	for _, st := range t.GetResolvedMarkdownStatements() {
+		files["statement/"+*st.Language+".md"] = []byte(t.headedStatementMarkdown(st, report))
+	}
+	for lang, pdf := range t.pdfStatements {
+		files["statement/"+lang+".pdf"] = pdf
+	}
+	for _, a := range t.assets {
+		files["statement/"+a.RelativePath] = a.Content
+	}
+	for _, e := range t.GetAllEditorials() {
+		report.dropped("editorial in %s", e.Language)
+	}
+
+	err = writeFiles(dirPath, files)
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func sortedIntKeys[V any](m map[int]V) []int {
+	res := make([]int, 0, len(m))
+	for k := range m {
+		res = append(res, k)
+	}
+	sort.Ints(res)
+	return res
+}
+
+// ImportTPS reads a task in the TPS layout, either a directory or a zip
+// archive. Every subtask becomes one test group.
+func ImportTPS(packagePath string) (*Task, *ImportReport, error) {
+	fsys, closePkg, err := openPackage(packagePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closePkg()
+
+	return ImportTPSFS(fsys)
+}
+
+// ImportTPSFS reads a task in the TPS layout rooted at fsys.
+func ImportTPSFS(fsys fs.FS) (*Task, *ImportReport, error) {
+	report := &ImportReport{Format: "tps"}
+
+	content, err := fs.ReadFile(fsys, "problem.json")
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading problem.json: %w", err)
+	}
+	var pj tpsProblemJSON
+	err = json.Unmarshal(content, &pj)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error parsing problem.json: %w", err)
+	}
+	if pj.Type != "" && pj.Type != "Batch" {
+		report.unmapped("task type %s", pj.Type)
+	}
+
+	name := pj.Title
+	if name == "" {
+		name = pj.Name
+	}
+	t, err := NewTask(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if pj.TimeLimit > 0 {
+		t.cpuTimeSeconds = pj.TimeLimit
+	}
+	if pj.MemoryLimit > 0 {
+		t.memoryMegabytes = pj.MemoryLimit
+	}
+
+	tests, err := readTPSTests(fsys, "tests", report)
+	if err != nil {
+		return nil, nil, err
+	}
+	testIDs := make(map[string]int, len(tests))
+	testNames := make([]string, 0, len(tests))
+	for _, tt := range tests {
+		id := t.AddTest(tt.input, tt.answer)
+		t.AssignFilenameToTest(tt.name, id)
+		testIDs[tt.name] = id
+		testNames = append(testNames, tt.name)
+	}
+
+	err = t.importTPSSubtasks(fsys, testIDs, testNames, report)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if pj.HasChecker {
+		t.checker, err = fs.ReadFile(fsys, "checker/checker.cpp")
+		if err != nil {
+			report.unmapped("has_checker without checker/checker.cpp")
+		}
+	}
+
+	examples, err := readTPSTests(fsys, "public/examples", report)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, e := range examples {
+		name := e.name
+		t.examples = append(t.examples, example{Input: e.input, Output: e.answer, Name: &name})
+	}
+
+	err = t.importTPSStatements(fsys, report)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, dir := range tpsUnmappedDirs {
+		if entries, err := fs.ReadDir(fsys, dir); err == nil && len(entries) > 0 {
+			report.unmapped("directory %s", dir)
+		}
+	}
+
+	return t, report, nil
+}
+
+// readTPSTests reads the <name>.in and <name>.out pairs of a directory sorted by name
+func readTPSTests(fsys fs.FS, dirPath string, report *ImportReport) ([]namedTest, error) {
+	entries, err := fs.ReadDir(fsys, dirPath)
+	if err != nil {
+		return nil, nil
+	}
+
+	res := make([]namedTest, 0)
+	for _, e := range entries {
+		if e.IsDir() || path.Ext(e.Name()) != ".in" {
+			continue
+		}
+		name := strings.TrimSuffix(e.Name(), ".in")
+		input, err := fs.ReadFile(fsys, path.Join(dirPath, e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("error reading test input: %w", err)
+		}
+		answer, err := fs.ReadFile(fsys, path.Join(dirPath, name+".out"))
+		if err != nil {
+			report.unmapped("test %s/%s without answer", dirPath, name)
+			continue
+		}
+		res = append(res, namedTest{name: name, input: input, answer: answer})
+	}
+	return res, nil
+}
+
+func (t *Task) importTPSSubtasks(fsys fs.FS, testIDs map[string]int, testNames []string, report *ImportReport) error {
+	entries, err := fs.ReadDir(fsys, "subtasks")
+	if err != nil {
+		return nil
+	}
+
+	// tests/mapping lists "<subtask name> <test name>" lines
+	mapping := make(map[string][]string)
+	if content, err := fs.ReadFile(fsys, "tests/mapping"); err == nil {
+		for _, line := range strings.Split(string(content), "\n") {
+			fields := strings.Fields(line)
+			if len(fields) == 2 {
+				mapping[fields[0]] = append(mapping[fields[0]], fields[1])
+			}
+		}
+	}
+
+	type subtask struct {
+		name string
+		tpsSubtaskJSON
+	}
+	subtasks := make([]subtask, 0)
+	for _, e := range entries {
+		if e.IsDir() || path.Ext(e.Name()) != ".json" {
+			continue
+		}
+		content, err := fs.ReadFile(fsys, path.Join("subtasks", e.Name()))
+		if err != nil {
+			return fmt.Errorf("error reading subtask: %w", err)
+		}
+		st := subtask{name: strings.TrimSuffix(e.Name(), ".json")}
+		err = json.Unmarshal(content, &st.tpsSubtaskJSON)
+		if err != nil {
+			return fmt.Errorf("error parsing subtask %s: %w", e.Name(), err)
+		}
+		subtasks = append(subtasks, st)
+	}
+	sort.SliceStable(subtasks, func(i, j int) bool { return subtasks[i].Index < subtasks[j].Index })
+
+	for i, st := range subtasks {
+		names := st.Testcases
+		if len(names) == 0 && len(st.Regex) > 0 {
+			for _, expr := range st.Regex {
+				re, err := regexp.Compile("^(?:" + expr + ")$")
+				if err != nil {
+					return fmt.Errorf("invalid regex of subtask %s: %w", st.name, err)
+				}
+				for _, name := range testNames {
+					if re.MatchString(name) && !containsString(names, name) {
+						names = append(names, name)
+					}
+				}
+			}
+		}
+		if len(names) == 0 {
+			names = mapping[st.name]
+		}
+
+		ids := make([]int, 0, len(names))
+		for _, name := range names {
+			id, ok := testIDs[name]
+			if !ok {
+				report.unmapped("subtask %s lists missing test %s", st.name, name)
+				continue
+			}
+			ids = append(ids, id)
+		}
+		err = t.AddTestGroupWithID(i+1, st.Score, false, ids, st.Index)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *Task) importTPSStatements(fsys fs.FS, report *ImportReport) error {
+	return fs.WalkDir(fsys, "statement", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == "statement" {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel := strings.TrimPrefix(p, "statement/")
+		content, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return fmt.Errorf("error reading statement file: %w", err)
+		}
+
+		lang := strings.TrimSuffix(rel, path.Ext(rel))
+		if lang == "index" {
+			lang = "en"
+		}
+		if _, err := normalizeLanguageCode(lang); err != nil || strings.Contains(rel, "/") {
+			return t.AddAsset(rel, content)
+		}
+		switch path.Ext(rel) {
+		case ".md":
+			intro, sections := splitMarkdownSections(string(content))
+			return t.SetMarkdownStatement(lang, statementFromSections(intro, sections, p, report))
+		case ".pdf":
+			return t.ReplacePDFStatement(lang, content)
+		}
+		return t.AddAsset(rel, content)
+	})
+}
@@ -0,0 +1,114 @@
+package fstaskparser_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/programme-lv/fs-task-format-parser/pkg/fstaskparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var tpsTaskPath = filepath.Join(prjRootPath, "testdata", "tps-aplusb")
+
+func TestImportingTPSTask(t *testing.T) {
+	task, report, err := fstaskparser.ImportTPS(tpsTaskPath)
+	require.NoErrorf(t, err, "failed to import task: %v", err)
+
+	assert.Equal(t, "A plus B", task.GetTaskName())
+	assert.Equal(t, 1.0, task.GetCPUTimeLimitInSeconds())
+	assert.Equal(t, 256, task.GetMemoryLimitInMegabytes())
+
+	// tests are read in name order: big1, big2, sample1, small1, small2
+	tests := task.GetTestsSortedByID()
+	require.Equal(t, 5, len(tests))
+	assert.Equal(t, "sample1", task.GetTestFilenameFromID(3))
+	assert.Equal(t, []byte("2000000000\n"), tests[0].Answer)
+
+	require.Equal(t, []int{1, 2, 3}, task.GetTestGroupIDs())
+	assert.Equal(t, fstaskparser.TestGroupInfo{GroupID: 1, Points: 0, Public: false, TestIDs: []int{3}, Subtask: 0}, task.GetInfoOnTestGroup(1))
+	assert.Equal(t, fstaskparser.TestGroupInfo{GroupID: 2, Points: 40, Public: false, TestIDs: []int{4, 5}, Subtask: 1}, task.GetInfoOnTestGroup(2))
+	assert.Equal(t, fstaskparser.TestGroupInfo{GroupID: 3, Points: 60, Public: false, TestIDs: []int{4, 5, 1, 2}, Subtask: 2}, task.GetInfoOnTestGroup(3))
+
+	examples := task.GetExamples()
+	require.Equal(t, 1, len(examples))
+	assert.Equal(t, []byte("1 2\n"), examples[0].Input)
+
+	st, err := task.GetMarkdownStatement("en")
+	require.NoErrorf(t, err, "failed to get statement: %v", err)
+	assert.Equal(t, "Given two integers $a$ and $b$, compute their sum.\n\n![](sum.png)\n", st.Story)
+	assert.Equal(t, "Print `a+b`.\n", st.Output)
+	require.NotNil(t, st.Scoring)
+	assert.Empty(t, task.FindMissingAssetReferences())
+
+	assert.Contains(t, string(task.GetCheckerSource()), "Wrong answer")
+	assert.Equal(t, []string{"directory solution", "directory validator"}, report.Unmapped)
+}
+
+func TestExportingTaskToTPS(t *testing.T) {
+	task, err := fstaskparser.Read(testTaskPath)
+	require.NoErrorf(t, err, "failed to read task: %v", err)
+
+	tmpDirectory, err := os.MkdirTemp("", "fstaskparser-test-")
+	require.NoErrorf(t, err, "failed to create temporary directory: %v", err)
+	defer os.RemoveAll(tmpDirectory)
+
+	outputDirectory := filepath.Join(tmpDirectory, "kvadrputekl")
+	report, err := task.ExportTPS(outputDirectory, fstaskparser.TPSExportOptions{})
+	require.NoErrorf(t, err, "failed to export task: %v", err)
+	assert.Contains(t, report.Dropped, "test group 1 is public")
+
+	subtask, err := os.ReadFile(filepath.Join(outputDirectory, "subtasks", "02.json"))
+	require.NoErrorf(t, err, "failed to read subtask: %v", err)
+	assert.JSONEq(t, `{"index": 2, "score": 8, "testcases": ["kp02a", "kp02b", "kp02c"]}`, string(subtask))
+
+	problem, err := os.ReadFile(filepath.Join(outputDirectory, "problem.json"))
+	require.NoErrorf(t, err, "failed to read problem.json: %v", err)
+	assert.JSONEq(t, `{"name": "kvadrputekl", "title": "Kvadrātveida putekļsūcējs", "type": "Batch",
+		"time_limit": 0.5, "memory_limit": 256, "has_checker": false}`, string(problem))
+
+	imported, _, err := fstaskparser.ImportTPS(outputDirectory)
+	require.NoErrorf(t, err, "failed to import exported task: %v", err)
+	assert.Equal(t, task.GetTaskName(), imported.GetTaskName())
+	assert.Equal(t, task.GetCPUTimeLimitInSeconds(), imported.GetCPUTimeLimitInSeconds())
+	assert.Equal(t, task.GetTestsSortedByID(), imported.GetTestsSortedByID())
+	require.Equal(t, task.GetTestGroupIDs(), imported.GetTestGroupIDs())
+	for _, id := range task.GetTestGroupIDs() {
+		expected := task.GetInfoOnTestGroup(id)
+		expected.Public = false
+		assert.Equal(t, expected, imported.GetInfoOnTestGroup(id))
+	}
+	assert.Equal(t, len(task.GetExamples()), len(imported.GetExamples()))
+
+	pdf, err := imported.GetPDFStatement("lv")
+	require.NoErrorf(t, err, "failed to get pdf statement: %v", err)
+	expectedPDF, _ := task.GetPDFStatement("lv")
+	assert.Equal(t, expectedPDF, pdf)
+
+	expectedSt, _ := task.GetMarkdownStatement("lv")
+	st, err := imported.GetMarkdownStatement("lv")
+	require.NoErrorf(t, err, "failed to get statement: %v", err)
+	assert.Equal(t, expectedSt.Input, st.Input)
+	assert.Equal(t, len(task.GetAssets()), len(imported.GetAssets()))
+}
+
+func TestExportingUngroupedTestsToTPS(t *testing.T) {
+	task, err := fstaskparser.Read(testTaskPath)
+	require.NoErrorf(t, err, "failed to read task: %v", err)
+	id := task.AddTest([]byte("1 1 1\n"), []byte("0\n"))
+	task.AssignFilenameToTest("kp03a", id)
+
+	tmpDirectory, err := os.MkdirTemp("", "fstaskparser-test-")
+	require.NoErrorf(t, err, "failed to create temporary directory: %v", err)
+	defer os.RemoveAll(tmpDirectory)
+
+	outputDirectory := filepath.Join(tmpDirectory, "kvadrputekl")
+	report, err := task.ExportTPS(outputDirectory, fstaskparser.TPSExportOptions{})
+	require.NoErrorf(t, err, "failed to export task: %v", err)
+	assert.Contains(t, report.Dropped, "1 tests outside of groups exported as the 0 point subtask 0")
+
+	subtask, err := os.ReadFile(filepath.Join(outputDirectory, "subtasks", "00.json"))
+	require.NoErrorf(t, err, "failed to read subtask: %v", err)
+	assert.JSONEq(t, `{"index": 0, "score": 0, "testcases": ["kp03a"]}`, string(subtask))
+}
@@ -0,0 +1,15 @@
+#include <fstream>
+#include <iostream>
+
+int main(int argc, char **argv) {
+    std::ifstream in(argv[1]), correct(argv[2]), out(argv[3]);
+    long long expected, got;
+    correct >> expected;
+    if (!(out >> got) || got != expected) {
+        std::cout << "0.0" << std::endl;
+        std::cerr << "Wrong answer" << std::endl;
+        return 0;
+    }
+    std::cout << "1.0" << std::endl;
+    std::cerr << "Correct" << std::endl;
+}
@@ -0,0 +1,13 @@
+#include <fstream>
+#include <iostream>
+
+// usage: validator input judge_answer feedback_dir < team_output
+int main(int argc, char **argv) {
+    std::ifstream judge(argv[2]);
+    long long expected, got;
+    judge >> expected;
+    if (!(std::cin >> got) || got != expected) {
+        return 43;
+    }
+    return 42;
+}
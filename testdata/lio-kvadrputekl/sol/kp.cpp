@@ -0,0 +1,2 @@
+// reference solution
+int main() {}
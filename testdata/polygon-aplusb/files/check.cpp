@@ -0,0 +1,10 @@
+#include "testlib.h"
+
+int main(int argc, char* argv[]) {
+    registerTestlibCmd(argc, argv);
+    long long expected = ans.readLong();
+    long long found = ouf.readLong();
+    if (expected != found)
+        quitf(_wa, "expected %lld, found %lld", expected, found);
+    quitf(_ok, "%lld", found);
+}
